@@ -0,0 +1,109 @@
+// Package bench repeatedly sends a single request to produce rough
+// latency and throughput numbers, for the --repeat/--concurrency mode.
+package bench
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/adamijak/http/internal/client"
+	"github.com/adamijak/http/internal/models"
+)
+
+// Stats aggregates the outcome of running the same request N times.
+type Stats struct {
+	Count       int
+	Errors      int
+	Min         time.Duration
+	Avg         time.Duration
+	Max         time.Duration
+	P95         time.Duration
+	StatusCodes map[int]int
+}
+
+// Run sends req n times across exactly concurrency worker goroutines
+// and returns aggregate timing and status-code stats. Each worker owns
+// a single client.New(opts) for its own serial slice of the n requests:
+// confining a Client to one goroutine avoids a data race, since
+// Client.send mutates fields like redirectWarnings with no
+// synchronization, while still letting that
+// worker's requests reuse keep-alive connections, unlike handing every
+// request its own short-lived Client.
+func Run(opts client.Options, req *models.Request, n, concurrency int) *Stats {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > n {
+		concurrency = n
+	}
+
+	var (
+		mu          sync.Mutex
+		durations   = make([]time.Duration, 0, n)
+		statusCodes = make(map[int]int)
+		errors      int
+	)
+
+	work := make(chan struct{}, n)
+	for i := 0; i < n; i++ {
+		work <- struct{}{}
+	}
+	close(work)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c := client.New(opts)
+			for range work {
+				resp, err := c.Do(req)
+
+				mu.Lock()
+				if err != nil {
+					errors++
+				} else {
+					durations = append(durations, resp.Timing)
+					statusCodes[resp.StatusCode]++
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return summarize(durations, statusCodes, errors)
+}
+
+func summarize(durations []time.Duration, statusCodes map[int]int, errors int) *Stats {
+	stats := &Stats{
+		Count:       len(durations),
+		Errors:      errors,
+		StatusCodes: statusCodes,
+	}
+	if len(durations) == 0 {
+		return stats
+	}
+
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	var sum time.Duration
+	for _, d := range durations {
+		sum += d
+	}
+
+	stats.Min = durations[0]
+	stats.Max = durations[len(durations)-1]
+	stats.Avg = sum / time.Duration(len(durations))
+	stats.P95 = durations[p95Index(len(durations))]
+	return stats
+}
+
+func p95Index(n int) int {
+	idx := int(float64(n) * 0.95)
+	if idx >= n {
+		idx = n - 1
+	}
+	return idx
+}
@@ -0,0 +1,72 @@
+package bench
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/adamijak/http/internal/client"
+	"github.com/adamijak/http/internal/models"
+)
+
+func TestRun_AggregatesCountAndStatusCodes(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := models.New()
+	req.Method = http.MethodGet
+	req.URL = u
+
+	stats := Run(client.Options{}, req, 5, 2)
+
+	if stats.Count != 5 {
+		t.Errorf("expected count 5, got %d", stats.Count)
+	}
+	if stats.StatusCodes[http.StatusTeapot] != 5 {
+		t.Errorf("expected 5 teapot responses, got %d", stats.StatusCodes[http.StatusTeapot])
+	}
+	if stats.Errors != 0 {
+		t.Errorf("expected no errors, got %d", stats.Errors)
+	}
+}
+
+func TestRun_ReusesKeepAliveConnectionsWithinEachWorker(t *testing.T) {
+	var newConns int32
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			atomic.AddInt32(&newConns, 1)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := models.New()
+	req.Method = http.MethodGet
+	req.URL = u
+
+	const n, concurrency = 20, 4
+	stats := Run(client.Options{}, req, n, concurrency)
+
+	if stats.Count != n {
+		t.Fatalf("expected count %d, got %d", n, stats.Count)
+	}
+	if got := atomic.LoadInt32(&newConns); got > concurrency {
+		t.Errorf("expected at most %d new connections (one per worker) for %d requests, got %d: keep-alive connections aren't being reused", concurrency, n, got)
+	}
+}
@@ -0,0 +1,168 @@
+// Package chain threads values captured from one response into later
+// requests in the same .http file, so a login-then-call style workflow
+// can reference an earlier response as "{{name}}". Every section of a
+// request that's resolved through Store (URL, headers, body) is
+// templated this way; a literal "{{name}}" that isn't meant as a
+// placeholder — e.g. a body that itself contains Mustache-style syntax
+// for some other system — is written as "\{{name}}" to escape it.
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// Store holds every value captured so far in a chain, keyed by name.
+type Store map[string]string
+
+// UndefinedVariableError reports a "{{Name}}" placeholder Resolve found
+// no value for, in the store, the environment, or a builtin. It's a
+// distinct type (rather than a plain fmt.Errorf) so a caller like
+// --prompt can recognize this specific failure via errors.As and offer
+// to fill the value in, instead of failing the request outright.
+type UndefinedVariableError struct {
+	Name string
+}
+
+func (e *UndefinedVariableError) Error() string {
+	return fmt.Sprintf("undefined variable {{%s}}", e.Name)
+}
+
+var varPattern = regexp.MustCompile(`(\\?)\{\{(\w+)\}\}`)
+
+// Resolve substitutes every "{{name}}" placeholder in text with its
+// captured value, falling back to the process environment variable of
+// the same name when the store has no capture (or --environment
+// profile variable) under that name. It fails clearly, naming the
+// placeholder, if neither has a value for it.
+//
+// Two names are reserved as builtins rather than looked up: "{{uuid}}"
+// and "{{randInt}}" generate a fresh random value on every occurrence,
+// from the source --seed controls.
+//
+// A placeholder preceded by a backslash, "\{{name}}", is left alone as
+// the literal text "{{name}}" (backslash stripped) instead of being
+// resolved, so a body that needs to contain that syntax verbatim can
+// escape it.
+func (s Store) Resolve(text string) (string, error) {
+	var firstErr error
+	result := varPattern.ReplaceAllStringFunc(text, func(m string) string {
+		if firstErr != nil {
+			return m
+		}
+		groups := varPattern.FindStringSubmatch(m)
+		escaped, name := groups[1], groups[2]
+		if escaped != "" {
+			return "{{" + name + "}}"
+		}
+		if gen, ok := builtins[name]; ok {
+			return gen()
+		}
+		if value, ok := s[name]; ok {
+			return value
+		}
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		firstErr = &UndefinedVariableError{Name: name}
+		return m
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return result, nil
+}
+
+// Capture extracts every value named in captures from resp and stores
+// it under its capture name, stopping at the first extraction that
+// fails.
+func (s Store) Capture(resp *models.HTTPResponse, captures []models.Capture) error {
+	for _, c := range captures {
+		value, err := extract(resp, c.Expr)
+		if err != nil {
+			return fmt.Errorf("capture %q: %w", c.Name, err)
+		}
+		s[c.Name] = value
+	}
+	return nil
+}
+
+func extract(resp *models.HTTPResponse, expr string) (string, error) {
+	switch {
+	case strings.HasPrefix(expr, "response.headers."):
+		name := strings.TrimPrefix(expr, "response.headers.")
+		value := resp.Header.Get(name)
+		if value == "" {
+			return "", fmt.Errorf("header %q not present in response", name)
+		}
+		return value, nil
+	case strings.HasPrefix(expr, "response.body.$."):
+		path := strings.TrimPrefix(expr, "response.body.$.")
+		return ExtractJSONPath(resp.Body, path)
+	default:
+		return "", fmt.Errorf("unrecognized capture expression %q", expr)
+	}
+}
+
+// pathSegmentPattern splits a single dotted path segment into an
+// optional object field name and any trailing "[N]" array indices, e.g.
+// "items[0]" into field "items" and index 0, or "[2]" into no field and
+// index 2.
+var pathSegmentPattern = regexp.MustCompile(`^([^\[]*)((?:\[\d+\])*)$`)
+
+var indexPattern = regexp.MustCompile(`\[(\d+)\]`)
+
+// ExtractJSONPath walks a dot-separated path of object field names and
+// "[N]" array indices (e.g. "data.items[0].id") through a JSON
+// document. It's shared by request-chaining captures
+// ("response.body.$.<path>") and --extract, which both navigate the
+// same way.
+func ExtractJSONPath(body, path string) (string, error) {
+	var doc any
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return "", fmt.Errorf("response body is not valid JSON: %w", err)
+	}
+
+	cur := doc
+	for _, segment := range strings.Split(path, ".") {
+		m := pathSegmentPattern.FindStringSubmatch(segment)
+		if m == nil {
+			return "", fmt.Errorf("path %q: invalid segment %q", path, segment)
+		}
+		field, indices := m[1], m[2]
+
+		if field != "" {
+			obj, ok := cur.(map[string]any)
+			if !ok {
+				return "", fmt.Errorf("path %q: %q is not an object field", path, field)
+			}
+			value, ok := obj[field]
+			if !ok {
+				return "", fmt.Errorf("path %q: field %q not found", path, field)
+			}
+			cur = value
+		}
+
+		for _, idxMatch := range indexPattern.FindAllStringSubmatch(indices, -1) {
+			idx, err := strconv.Atoi(idxMatch[1])
+			if err != nil {
+				return "", fmt.Errorf("path %q: invalid array index %q", path, idxMatch[1])
+			}
+			arr, ok := cur.([]any)
+			if !ok {
+				return "", fmt.Errorf("path %q: %q is not an array", path, segment)
+			}
+			if idx < 0 || idx >= len(arr) {
+				return "", fmt.Errorf("path %q: index %d out of range (length %d)", path, idx, len(arr))
+			}
+			cur = arr[idx]
+		}
+	}
+	return fmt.Sprint(cur), nil
+}
@@ -0,0 +1,148 @@
+package chain
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+func TestStore_ResolveSubstitutesCapturedValue(t *testing.T) {
+	s := Store{"token": "abc123"}
+	got, err := s.Resolve("Bearer {{token}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Bearer abc123" {
+		t.Errorf("got %q, want %q", got, "Bearer abc123")
+	}
+}
+
+func TestStore_ResolveFailsOnUndefinedVariable(t *testing.T) {
+	s := Store{}
+	if _, err := s.Resolve("Bearer {{token}}"); err == nil {
+		t.Fatal("expected an error for an undefined variable")
+	}
+}
+
+func TestStore_ResolveLeavesEscapedPlaceholderLiteral(t *testing.T) {
+	s := Store{"token": "abc123"}
+	got, err := s.Resolve(`{"template":"\{{token}}","real":"{{token}}"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"template":"{{token}}","real":"abc123"}`
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestStore_ResolveEscapedPlaceholderNeedsNoDefinition(t *testing.T) {
+	s := Store{}
+	got, err := s.Resolve(`\{{undefined}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "{{undefined}}" {
+		t.Errorf("got %q, want %q", got, "{{undefined}}")
+	}
+}
+
+func TestStore_ResolveSeededUUIDAndRandIntAreDeterministic(t *testing.T) {
+	SetSeed(42)
+	s := Store{}
+	got1, err := s.Resolve("{{uuid}} {{randInt}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	SetSeed(42)
+	got2, err := s.Resolve("{{uuid}} {{randInt}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got1 != got2 {
+		t.Errorf("expected the same seed to produce identical output, got %q and %q", got1, got2)
+	}
+}
+
+func TestStore_ResolveUUIDAndRandIntDontRequireADefinition(t *testing.T) {
+	s := Store{}
+	got, err := s.Resolve("{{uuid}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == "{{uuid}}" || got == "" {
+		t.Errorf("expected {{uuid}} to resolve to a generated value, got %q", got)
+	}
+}
+
+func TestStore_CaptureFromJSONBody(t *testing.T) {
+	resp := &models.HTTPResponse{Body: `{"data":{"access_token":"tok-1"}}`}
+	s := Store{}
+	err := s.Capture(resp, []models.Capture{{Name: "token", Expr: "response.body.$.data.access_token"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s["token"] != "tok-1" {
+		t.Errorf("got %q, want %q", s["token"], "tok-1")
+	}
+}
+
+func TestStore_CaptureFromHeader(t *testing.T) {
+	resp := &models.HTTPResponse{Header: http.Header{"Location": []string{"/orders/42"}}}
+	s := Store{}
+	err := s.Capture(resp, []models.Capture{{Name: "loc", Expr: "response.headers.Location"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s["loc"] != "/orders/42" {
+		t.Errorf("got %q, want %q", s["loc"], "/orders/42")
+	}
+}
+
+func TestStore_CaptureFailsOnMissingField(t *testing.T) {
+	resp := &models.HTTPResponse{Body: `{"data":{}}`}
+	s := Store{}
+	err := s.Capture(resp, []models.Capture{{Name: "token", Expr: "response.body.$.data.access_token"}})
+	if err == nil {
+		t.Fatal("expected an error for a missing field")
+	}
+}
+
+func TestExtractJSONPath_ArrayIndex(t *testing.T) {
+	body := `{"data":{"items":[{"id":"a"},{"id":"b"}]}}`
+	got, err := ExtractJSONPath(body, "data.items[1].id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "b" {
+		t.Errorf("got %q, want %q", got, "b")
+	}
+}
+
+func TestExtractJSONPath_ArrayIndexOutOfRangeErrors(t *testing.T) {
+	body := `{"items":[1,2]}`
+	if _, err := ExtractJSONPath(body, "items[5]"); err == nil {
+		t.Fatal("expected an error for an out-of-range index")
+	}
+}
+
+func TestExtractJSONPath_MissingPathErrors(t *testing.T) {
+	body := `{"data":{}}`
+	if _, err := ExtractJSONPath(body, "data.missing"); err == nil {
+		t.Fatal("expected an error for a missing path")
+	}
+}
+
+func TestExtractJSONPath_NestedObjectField(t *testing.T) {
+	body := `{"data":{"user":{"name":"ada"}}}`
+	got, err := ExtractJSONPath(body, "data.user.name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "ada" {
+		t.Errorf("got %q, want %q", got, "ada")
+	}
+}
@@ -0,0 +1,55 @@
+package chain
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// randSource backs the "{{uuid}}" and "{{randInt}}" builtin
+// placeholders. It defaults to a time-seeded source so ordinary runs
+// still get real randomness; --seed reseeds it deterministically so
+// golden/snapshot tests can assert byte-identical output across runs.
+var (
+	randMu     sync.Mutex
+	randSource = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// SetSeed reseeds the shared source used by "{{uuid}}" and
+// "{{randInt}}", so every placeholder resolved afterward is
+// deterministic for a given seed and a given sequence of placeholders.
+func SetSeed(seed int64) {
+	randMu.Lock()
+	defer randMu.Unlock()
+	randSource = rand.New(rand.NewSource(seed))
+}
+
+// builtins resolves the reserved placeholder names that generate a
+// fresh value on every occurrence, rather than looking one up from the
+// store or the environment.
+var builtins = map[string]func() string{
+	"uuid":    randUUID,
+	"randInt": randInt,
+}
+
+// randUUID returns a random RFC 4122 version 4 UUID.
+func randUUID() string {
+	randMu.Lock()
+	var b [16]byte
+	randSource.Read(b[:])
+	randMu.Unlock()
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// randInt returns a random non-negative int, matching math/rand.Int's
+// range.
+func randInt() string {
+	randMu.Lock()
+	n := randSource.Int()
+	randMu.Unlock()
+	return fmt.Sprint(n)
+}
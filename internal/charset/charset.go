@@ -0,0 +1,68 @@
+// Package charset decodes a response body's bytes to UTF-8 based on the
+// charset named in its Content-Type header. The project takes no
+// third-party dependencies, so golang.org/x/text/encoding isn't an
+// option here; Decode instead hand-rolls the single-byte Western
+// charsets most commonly seen in the wild. Multi-byte legacy charsets
+// like Shift_JIS and GBK would need mapping tables far larger than is
+// practical to hand-roll correctly, so they're treated the same as any
+// other unrecognized name: Decode reports ok=false and the caller falls
+// back to the raw bytes with a warning.
+package charset
+
+import "strings"
+
+// Decode converts data from the named charset to a UTF-8 string. name is
+// matched case-insensitively; an empty name, "utf-8", or "us-ascii" is
+// returned unchanged (ok=true), since UTF-8 is both the common case and
+// HTTP's default per RFC 7231 section 3.1.1.5. Decode reports ok=false
+// for any charset it doesn't recognize or support, so the caller can
+// fall back to the raw bytes instead of silently mangling them.
+func Decode(data []byte, name string) (decoded string, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "utf-8", "utf8", "us-ascii", "ascii":
+		return string(data), true
+	case "iso-8859-1", "latin1", "iso8859-1", "cp819":
+		return decodeLatin1(data), true
+	case "windows-1252", "cp1252", "x-cp1252":
+		return decodeWindows1252(data), true
+	default:
+		return "", false
+	}
+}
+
+// decodeLatin1 converts ISO-8859-1 bytes to UTF-8. ISO-8859-1 maps every
+// byte value directly to the identically numbered Unicode code point, so
+// decoding is just a rune-per-byte widening.
+func decodeLatin1(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// windows1252High replaces ISO-8859-1's C1 control block (0x80-0x9F)
+// with the printable characters Windows-1252 assigns there instead
+// (curly quotes, dashes, and a handful of extra letters); everything
+// below 0x80 and from 0xA0 up is identical to ISO-8859-1. Indexes 0x81,
+// 0x8D, 0x8F, 0x90, and 0x9D are unassigned in Windows-1252 and decode
+// to U+FFFD, the Unicode replacement character.
+var windows1252High = [32]rune{
+	0x20AC, 0xFFFD, 0x201A, 0x0192, 0x201E, 0x2026, 0x2020, 0x2021,
+	0x02C6, 0x2030, 0x0160, 0x2039, 0x0152, 0xFFFD, 0x017D, 0xFFFD,
+	0xFFFD, 0x2018, 0x2019, 0x201C, 0x201D, 0x2022, 0x2013, 0x2014,
+	0x02DC, 0x2122, 0x0161, 0x203A, 0x0153, 0xFFFD, 0x017E, 0x0178,
+}
+
+// decodeWindows1252 converts Windows-1252 bytes to UTF-8.
+func decodeWindows1252(data []byte) string {
+	runes := make([]rune, len(data))
+	for i, b := range data {
+		if b >= 0x80 && b <= 0x9F {
+			runes[i] = windows1252High[b-0x80]
+		} else {
+			runes[i] = rune(b)
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,53 @@
+package charset
+
+import "testing"
+
+func TestDecode_Latin1AccentedCharacters(t *testing.T) {
+	// "café crème" in ISO-8859-1: é is 0xE9.
+	input := []byte{'c', 'a', 'f', 0xE9, ' ', 'c', 'r', 0xE8, 'm', 'e'}
+	got, ok := Decode(input, "ISO-8859-1")
+	if !ok {
+		t.Fatal("expected ISO-8859-1 to be supported")
+	}
+	if want := "café crème"; got != want {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+}
+
+func TestDecode_Windows1252SmartQuotes(t *testing.T) {
+	// 0x93/0x94 are left/right curly double quotes in Windows-1252,
+	// which ISO-8859-1 leaves as C1 control codes.
+	input := []byte{0x93, 'h', 'i', 0x94}
+	got, ok := Decode(input, "windows-1252")
+	if !ok {
+		t.Fatal("expected windows-1252 to be supported")
+	}
+	if want := "“hi”"; got != want {
+		t.Errorf("Decode() = %q, want %q", got, want)
+	}
+}
+
+func TestDecode_UTF8AndEmptyPassThroughUnchanged(t *testing.T) {
+	input := []byte("hello ☃")
+	for _, name := range []string{"", "utf-8", "UTF8", "us-ascii"} {
+		got, ok := Decode(input, name)
+		if !ok {
+			t.Fatalf("Decode(%q) reported unsupported", name)
+		}
+		if got != string(input) {
+			t.Errorf("Decode(%q) = %q, want unchanged input", name, got)
+		}
+	}
+}
+
+func TestDecode_UnrecognizedCharsetReportsNotOK(t *testing.T) {
+	if _, ok := Decode([]byte("data"), "Shift_JIS"); ok {
+		t.Error("expected Shift_JIS to be unsupported")
+	}
+	if _, ok := Decode([]byte("data"), "GBK"); ok {
+		t.Error("expected GBK to be unsupported")
+	}
+	if _, ok := Decode([]byte("data"), "bogus-charset"); ok {
+		t.Error("expected an unknown charset name to be unsupported")
+	}
+}
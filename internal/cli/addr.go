@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitBracketedHost splits a leading host off s and returns whatever
+// follows its separating ":". It understands the "[host]:rest" form an
+// IPv6 literal (optionally carrying a "%zone" suffix, e.g.
+// "[fe80::1%eth0]") needs to keep its own colons from being confused
+// with that separator; anything not starting with "[" falls back to
+// splitting on the first ":", which is exactly right for hostnames,
+// IPv4 literals, and--in --resolve/--connect-to's flag syntax--for a
+// trailing addr-only field that doesn't need brackets in the first
+// place.
+func splitBracketedHost(s string) (host, rest string, err error) {
+	if strings.HasPrefix(s, "[") {
+		end := strings.IndexByte(s, ']')
+		if end < 0 {
+			return "", "", fmt.Errorf("unterminated %q in %q", "[", s)
+		}
+		if end+1 >= len(s) || s[end+1] != ':' {
+			return "", "", fmt.Errorf("expected %q after %q in %q", ":", "]", s)
+		}
+		return s[1:end], s[end+2:], nil
+	}
+	host, rest, ok := strings.Cut(s, ":")
+	if !ok {
+		return "", "", fmt.Errorf("missing %q in %q", ":", s)
+	}
+	return host, rest, nil
+}
+
+// trimBrackets strips a single enclosing "[...]" from s, if present, so
+// a bracketed IPv6 literal given for a field that doesn't itself need
+// brackets (because nothing ambiguous follows it) still works.
+func trimBrackets(s string) string {
+	if strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
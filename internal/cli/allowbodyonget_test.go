@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_BodyOnGetWarnsByDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	body := "GET " + srv.URL + "/_search\nContent-Type: application/json\n\n{\"query\": {}}\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "--allow-body-on-get") {
+		t.Errorf("expected a body-on-GET warning, got %s", stderr.String())
+	}
+}
+
+func TestRun_AllowBodyOnGetSuppressesTheWarningAndSendsTheBody(t *testing.T) {
+	var receivedBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		receivedBody = string(b)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	body := "GET " + srv.URL + "/_search\nContent-Type: application/json\n\n{\"query\": {}}\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--allow-body-on-get", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if strings.Contains(stderr.String(), "--allow-body-on-get") {
+		t.Errorf("expected no body-on-GET warning, got %s", stderr.String())
+	}
+	if receivedBody != `{"query": {}}` {
+		t.Errorf("expected the body to reach the server, got %q", receivedBody)
+	}
+}
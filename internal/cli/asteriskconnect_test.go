@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_NoSendOptionsAsteriskFormWireOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	content := "OPTIONS * HTTP/1.1\nHost: example.com\n\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--no-send", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "OPTIONS * HTTP/1.1\n") {
+		t.Errorf("expected an asterisk-form request line, got %q", stdout.String())
+	}
+}
+
+func TestRun_NoSendConnectWireOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	content := "CONNECT example.com:443 HTTP/1.1\n\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--no-send", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "CONNECT example.com:443 HTTP/1.1\n") {
+		t.Errorf("expected a CONNECT authority-form request line, got %q", stdout.String())
+	}
+}
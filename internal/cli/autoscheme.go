@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/adamijak/http/internal/client"
+	"github.com/adamijak/http/internal/models"
+)
+
+// sendWithAutoScheme sends req with c, and, when autoScheme is set and
+// the first attempt fails with a *client.SchemeMismatchError, swaps
+// req.URL's scheme to the one the error suggests and retries exactly
+// once, so a request against the wrong scheme's port succeeds instead
+// of requiring the caller to notice and rerun with --http/--https.
+func sendWithAutoScheme(c *client.Client, req *models.Request, autoScheme bool) (*models.HTTPResponse, error) {
+	resp, err := c.Do(req)
+	if err == nil || !autoScheme {
+		return resp, err
+	}
+	var mismatch *client.SchemeMismatchError
+	if !errors.As(err, &mismatch) {
+		return resp, err
+	}
+	req.URL.Scheme = mismatch.SuggestedScheme
+	return c.Do(req)
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_AutoSchemeRetriesWithCorrectedScheme(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello world")
+	}))
+	defer srv.Close()
+
+	secureURL := "https://" + strings.TrimPrefix(srv.URL, "http://") + "/"
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+secureURL+"\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--auto-scheme", "--body-only", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if got := stdout.String(); got != "hello world\n" {
+		t.Errorf("expected the retried request's body, got %q", got)
+	}
+}
+
+func TestRun_WithoutAutoSchemeFailsOnSchemeMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "hello world")
+	}))
+	defer srv.Close()
+
+	secureURL := "https://" + strings.TrimPrefix(srv.URL, "http://") + "/"
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+secureURL+"\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit without --auto-scheme")
+	}
+	if !strings.Contains(stderr.String(), "--http") {
+		t.Errorf("expected the error to suggest --http, got %q", stderr.String())
+	}
+}
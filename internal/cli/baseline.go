@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// baselineResponse is the on-disk shape a --save-baseline file captures
+// and a --baseline comparison reads back: just the parts of a response a
+// regression test cares about, not transport details like Timing.
+type baselineResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header"`
+	Body       string      `json:"body"`
+}
+
+// saveBaseline writes resp's status, headers, and body to path as JSON,
+// for a later --baseline comparison.
+func saveBaseline(path string, resp *models.HTTPResponse) error {
+	data, err := json.MarshalIndent(baselineResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       resp.Body,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("http: --save-baseline: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("http: --save-baseline: %w", err)
+	}
+	return nil
+}
+
+// loadBaseline reads back a response file written by saveBaseline.
+func loadBaseline(path string) (baselineResponse, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return baselineResponse{}, fmt.Errorf("http: --baseline: %w", err)
+	}
+	var b baselineResponse
+	if err := json.Unmarshal(data, &b); err != nil {
+		return baselineResponse{}, fmt.Errorf("http: --baseline: parsing %s: %w", path, err)
+	}
+	return b, nil
+}
+
+// baselineDefaultIgnoredHeaders are dropped from both sides of a
+// --baseline comparison unconditionally, since they legitimately change
+// on every run and would otherwise drown out real regressions.
+var baselineDefaultIgnoredHeaders = []string{"Date"}
+
+// diffBaseline renders resp's differences from baseline as a unified
+// diff, comparing status line, headers (sorted by name, ignoring any in
+// ignoreHeaders and baselineDefaultIgnoredHeaders), and body. It returns
+// "" when there is no difference.
+func diffBaseline(baseline baselineResponse, resp *models.HTTPResponse, ignoreHeaders []string) string {
+	ignored := make(map[string]bool, len(ignoreHeaders)+len(baselineDefaultIgnoredHeaders))
+	for _, name := range baselineDefaultIgnoredHeaders {
+		ignored[http.CanonicalHeaderKey(name)] = true
+	}
+	for _, name := range ignoreHeaders {
+		ignored[http.CanonicalHeaderKey(name)] = true
+	}
+
+	before := renderBaselineLines(baseline.StatusCode, baseline.Header, baseline.Body, ignored)
+	after := renderBaselineLines(resp.StatusCode, resp.Header, resp.Body, ignored)
+
+	diff := unifiedDiff("baseline", "response", before, after)
+	return diff
+}
+
+// renderBaselineLines formats a response's status, sorted headers, and
+// body as plain lines, the textual form unifiedDiff compares.
+func renderBaselineLines(statusCode int, header http.Header, body string, ignored map[string]bool) []string {
+	lines := []string{fmt.Sprintf("status: %d", statusCode)}
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if ignored[name] {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			lines = append(lines, fmt.Sprintf("%s: %s", name, value))
+		}
+	}
+
+	lines = append(lines, "")
+	lines = append(lines, strings.Split(body, "\n")...)
+	return lines
+}
@@ -0,0 +1,77 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_SaveBaselineThenBaselineDetectsHeaderChange(t *testing.T) {
+	dir := t.TempDir()
+	reqPath := filepath.Join(dir, "req.http")
+	baselinePath := filepath.Join(dir, "baseline.json")
+
+	version := "v1"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-App-Version", version)
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	if err := os.WriteFile(reqPath, []byte(fmt.Sprintf("GET %s/\n\n", srv.URL)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--save-baseline", baselinePath, reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected --save-baseline to exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !fileExists(baselinePath) {
+		t.Fatal("expected --save-baseline to write a baseline file")
+	}
+
+	stdout.Reset()
+	stderr.Reset()
+	code = Run([]string{"--baseline", baselinePath, reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected an unchanged response to exit 0, got %d, stdout=%s", code, stdout.String())
+	}
+
+	version = "v2"
+	stdout.Reset()
+	stderr.Reset()
+	code = Run([]string{"--baseline", baselinePath, reqPath}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a changed header to make --baseline exit non-zero")
+	}
+	out := stdout.String()
+	if !strings.Contains(out, "- X-App-Version: v1") {
+		t.Errorf("expected the diff to show the removed old header value, got %q", out)
+	}
+	if !strings.Contains(out, "+ X-App-Version: v2") {
+		t.Errorf("expected the diff to show the added new header value, got %q", out)
+	}
+}
+
+func TestRun_BaselineAndSaveBaselineAreMutuallyExclusive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--baseline", filepath.Join(dir, "a.json"), "--save-baseline", filepath.Join(dir, "b.json"), path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "mutually exclusive") {
+		t.Errorf("expected an error mentioning mutual exclusivity, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// hashBody computes algorithm's hex digest of body: sha256, sha1, or
+// md5.
+func hashBody(algorithm string, body []byte) (string, error) {
+	switch strings.ToLower(algorithm) {
+	case "sha256":
+		sum := sha256.Sum256(body)
+		return hex.EncodeToString(sum[:]), nil
+	case "sha1":
+		sum := sha1.Sum(body)
+		return hex.EncodeToString(sum[:]), nil
+	case "md5":
+		sum := md5.Sum(body)
+		return hex.EncodeToString(sum[:]), nil
+	default:
+		return "", fmt.Errorf("http: invalid --hash %q, expected sha256, sha1, or md5", algorithm)
+	}
+}
@@ -0,0 +1,114 @@
+package cli
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashBody_ComputesKnownDigests(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		want      string
+	}{
+		{"sha256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{"sha1", "aaf4c61ddcc5e8a2dabede0f3b482cd9aea9434d"},
+		{"md5", "5d41402abc4b2a76b9719d911017c592"},
+	}
+	for _, tt := range tests {
+		got, err := hashBody(tt.algorithm, []byte("hello"))
+		if err != nil {
+			t.Fatalf("hashBody(%q): %v", tt.algorithm, err)
+		}
+		if got != tt.want {
+			t.Errorf("hashBody(%q) = %q, want %q", tt.algorithm, got, tt.want)
+		}
+	}
+}
+
+func TestHashBody_RejectsUnsupportedAlgorithm(t *testing.T) {
+	if _, err := hashBody("sha512", []byte("hello")); err == nil {
+		t.Fatal("expected an error for an unsupported algorithm")
+	}
+}
+
+func TestRun_HashPrintsDigestAfterTheResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--hash", "sha256", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	sum := sha256.Sum256([]byte("hello world"))
+	want := "sha256: " + hex.EncodeToString(sum[:])
+	if !bytes.Contains(stdout.Bytes(), []byte(want)) {
+		t.Errorf("expected stdout to contain %q, got %q", want, stdout.String())
+	}
+}
+
+func TestRun_ExpectHashPassesForAMatchingChecksum(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte("hello world"))
+	digest := hex.EncodeToString(sum[:])
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--expect-hash", digest, path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0 for a matching --expect-hash, got %d, stderr=%s", code, stderr.String())
+	}
+}
+
+func TestRun_ExpectHashFailsForAMismatchedChecksum(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--expect-hash", "0000000000000000000000000000000000000000000000000000000000000000", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for a mismatched --expect-hash")
+	}
+}
+
+func TestRun_InvalidHashAlgorithmExitsWithUsageError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\nHost: example.com\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--hash", "sha512", path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d", code)
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_BodyStdinInjectsBodyFromStdinIntoFileTemplate(t *testing.T) {
+	var gotBody []byte
+	var gotContentLength string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotBody, _ = io.ReadAll(r.Body)
+		gotContentLength = r.Header.Get("Content-Length")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("POST "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-stdin", path}, strings.NewReader(`{"from":"stdin"}`), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if string(gotBody) != `{"from":"stdin"}` {
+		t.Errorf("expected the piped body, got %q", gotBody)
+	}
+	if gotContentLength != "16" {
+		t.Errorf("expected Content-Length recomputed to 16, got %q", gotContentLength)
+	}
+}
+
+func TestRun_BodyStdinWithoutStdinErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("POST https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-stdin", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit when --body-stdin has no stdin to read")
+	}
+}
+
+func TestRun_BodyStdinAndReplAreMutuallyExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("POST https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-stdin", "--repl", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --body-stdin combined with --repl")
+	}
+}
@@ -0,0 +1,84 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRun_CacheDirRevalidatesAndReusesBodyOn304 runs the same request
+// twice against a server that honors If-None-Match, --cache-dir set
+// both times: the first run gets a full 200 body and caches its ETag,
+// the second sends that ETag back, gets a 304 with no body, and prints
+// the body --cache-dir kept from the first run instead.
+func TestRun_CacheDirRevalidatesAndReusesBodyOn304(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("ETag", `"v1"`)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fmt.Fprint(w, "the current value")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cacheDir := filepath.Join(dir, "cache")
+
+	var first bytes.Buffer
+	if code := Run([]string{"--cache-dir", cacheDir, path}, nil, &first, &bytes.Buffer{}); code != 0 {
+		t.Fatalf("expected exit 0 for the first run, got %d", code)
+	}
+	if !bytes.Contains(first.Bytes(), []byte("the current value")) {
+		t.Errorf("expected the first response to include the body, got %q", first.String())
+	}
+
+	var second, secondErr bytes.Buffer
+	if code := Run([]string{"--cache-dir", cacheDir, path}, nil, &second, &secondErr); code != 0 {
+		t.Fatalf("expected exit 0 for the second run, got %d, stderr=%s", code, secondErr.String())
+	}
+	if requests != 2 {
+		t.Fatalf("expected the server to see 2 requests, got %d", requests)
+	}
+	if !bytes.Contains(second.Bytes(), []byte("304")) {
+		t.Errorf("expected the second response to be a 304, got %q", second.String())
+	}
+	if !bytes.Contains(second.Bytes(), []byte("the current value")) {
+		t.Errorf("expected the second response to reuse the cached body, got %q", second.String())
+	}
+}
+
+// TestRun_CacheDirNotesMissingBodyOn304 covers a 304 with nothing in
+// --cache-dir to fill it in from (e.g. the cache file was removed):
+// the tool should say so rather than silently printing an empty body.
+func TestRun_CacheDirNotesMissingBodyOn304(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--cache-dir", filepath.Join(dir, "cache"), path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("no cached body")) {
+		t.Errorf("expected a note about the missing cached body, got %q", stderr.String())
+	}
+}
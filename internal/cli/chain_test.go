@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_ChainsCaptureFromFirstResponseIntoSecondRequest(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			fmt.Fprint(w, `{"access_token":"tok-abc"}`)
+		case "/me":
+			gotAuth = r.Header.Get("Authorization")
+		}
+	}))
+	defer srv.Close()
+
+	content := "### login\n" +
+		"POST " + srv.URL + "/login\n" +
+		"@token = response.body.$.access_token\n\n" +
+		"### whoami\n" +
+		"GET " + srv.URL + "/me\n" +
+		"Authorization: Bearer {{token}}\n\n"
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotAuth != "Bearer tok-abc" {
+		t.Errorf("expected the second request to carry the captured token, got Authorization=%q", gotAuth)
+	}
+	if !strings.Contains(stdout.String(), "tok-abc") {
+		t.Errorf("expected the first response to be printed too, got %q", stdout.String())
+	}
+}
+
+func TestRun_UndefinedVariableFailsClearly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	content := "GET https://example.com/\nAuthorization: Bearer {{token}}\n\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected non-zero exit for an undefined variable reference")
+	}
+	if !strings.Contains(stderr.String(), "token") {
+		t.Errorf("expected the error to name the undefined variable, got %q", stderr.String())
+	}
+}
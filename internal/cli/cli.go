@@ -0,0 +1,1775 @@
+// Package cli wires together flag parsing, request building, and
+// sending for the http command-line tool.
+package cli
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/adamijak/http/internal/bench"
+	"github.com/adamijak/http/internal/chain"
+	"github.com/adamijak/http/internal/client"
+	"github.com/adamijak/http/internal/config"
+	"github.com/adamijak/http/internal/cookiejar"
+	"github.com/adamijak/http/internal/envfile"
+	"github.com/adamijak/http/internal/eventlog"
+	"github.com/adamijak/http/internal/export"
+	"github.com/adamijak/http/internal/httpfile"
+	"github.com/adamijak/http/internal/models"
+	"github.com/adamijak/http/internal/openapi"
+	"github.com/adamijak/http/internal/render"
+	"github.com/adamijak/http/internal/reqcache"
+	"github.com/adamijak/http/internal/reqjson"
+	"github.com/adamijak/http/internal/sign"
+	"github.com/adamijak/http/internal/sizeutil"
+	"github.com/adamijak/http/internal/theme"
+	"github.com/adamijak/http/internal/validate"
+)
+
+// Run parses args and executes the tool, reading from stdin (used by
+// --repl) and writing output to stdout/stderr. stdin may be nil for
+// callers that never need it, which --repl treats as EOF. It returns
+// the process exit code.
+func Run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("http", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	// safeStdin is stdin with Run's documented "may be nil" replaced by
+	// an already-empty reader, so --repl, --websocket, and --prompt can
+	// all read from it unconditionally instead of each nil-checking it.
+	safeStdin := stdin
+	if safeStdin == nil {
+		safeStdin = strings.NewReader("")
+	}
+
+	var (
+		noSend                bool
+		validateOnly          bool
+		query                 keyValueList
+		timing                bool
+		repeat                int
+		concurrency           int
+		allowNonIdempotent    bool
+		cookieJarPath         string
+		forceHTTP             bool
+		forceHTTPS            bool
+		proxy                 string
+		requestTarget         string
+		maxBodySize           string
+		strict                bool
+		insecure              bool
+		colorMode             string
+		themeName             string
+		noAutoHeaders         bool
+		skipCheck             stringList
+		verbose               bool
+		verboseShowSecrets    bool
+		list                  bool
+		selectFlag            string
+		timeout               string
+		timeoutConnect        string
+		maxTime               string
+		configPath            string
+		urlEncode             bool
+		allowBodyOnGet        bool
+		formFields            formFieldList
+		formFiles             formFileList
+		dataFields            formFieldList
+		harPath               string
+		recordPath            string
+		replayPath            string
+		resolveOverrides      resolveList
+		connectToOverrides    connectToList
+		maxHeaderSize         string
+		maxHeadersSize        string
+		maxRequestLineSize    string
+		headerCase            string
+		bodyOnly              bool
+		stream                bool
+		fail                  bool
+		repl                  bool
+		websocket             bool
+		prompt                bool
+		maxResponseTime       string
+		sni                   string
+		noBody                bool
+		bodyStdin             bool
+		openapiSpec           string
+		openapiOperation      string
+		openapiParams         openapiParamList
+		extractPath           string
+		continueOnError       bool
+		environment           string
+		outputPath            string
+		resume                bool
+		lint                  bool
+		lintFormat            string
+		format                string
+		signScheme            string
+		awsRegion             string
+		awsService            string
+		raw                   bool
+		target                string
+		head                  bool
+		discover              bool
+		effectiveURL          bool
+		seed                  int64
+		expectStatus          stringList
+		expectHeader          stringList
+		expectBodyContains    stringList
+		methodOverride        string
+		headerFlags           headerList
+		jsonBody              string
+		contentTypeAlias      string
+		parallel              int
+		ntlmAuth              bool
+		digestAuth            bool
+		pins                  stringList
+		noRedirectTo          stringList
+		uploadFile            string
+		headersFile           string
+		baselinePath          string
+		saveBaselinePath      string
+		baselineIgnoreHeaders stringList
+		maxRedirects          int
+		locationTrusted       bool
+		user                  string
+		maxHeaderLines        int
+		printSpec             string
+		compress              string
+		autoScheme            bool
+		userAgent             string
+		noDefaultHeaders      bool
+		savePath              string
+		watch                 bool
+		maxResponseHeaderSize string
+		cacheDir              string
+		failWithBody          bool
+		logFilePath           string
+		trimResponse          int
+		trimLines             int
+		sortHeaders           bool
+		hostOverride          string
+		hashAlgo              string
+		expectHash            string
+		proxyCACertPath       string
+		proxyInsecure         bool
+		failEarly             bool
+		failEarlyTimeout      string
+		retry                 int
+		retryDelay            string
+		retryOnStatus         string
+		summary               string
+	)
+	fs.BoolVar(&noSend, "no-send", false, "print the request instead of sending it")
+	fs.BoolVar(&validateOnly, "validate-only", false, "validate the request and print a pass/fail report instead of printing or sending it, exiting non-zero on errors (or, with --strict, warnings); cleaner for CI than --no-send, which prints the whole request")
+	fs.Var(&query, "query", "append a query parameter (key=value); may be repeated")
+	fs.Var(&query, "q", "shorthand for --query")
+	fs.BoolVar(&timing, "timing", false, "print the round-trip duration after the response")
+	fs.IntVar(&repeat, "repeat", 0, "send the request N times and print aggregate stats instead of the response")
+	fs.IntVar(&concurrency, "concurrency", 1, "number of goroutines to use with --repeat")
+	fs.BoolVar(&allowNonIdempotent, "allow-non-idempotent", false, "allow --repeat with non-idempotent methods (e.g. POST)")
+	fs.StringVar(&cookieJarPath, "cookie-jar", "", "persist cookies to this file across invocations")
+	fs.StringVar(&cacheDir, "cache-dir", "", "cache ETag/Last-Modified validators (and the matching body) per URL under this directory, adding If-None-Match/If-Modified-Since to later requests and reusing the cached body on a 304")
+	fs.BoolVar(&forceHTTP, "http", false, "force the request scheme to http, regardless of the file")
+	fs.BoolVar(&forceHTTPS, "https", false, "force the request scheme to https, regardless of the file")
+	fs.StringVar(&proxy, "proxy", "", "send the request through this HTTP proxy, using absolute-form request targets")
+	fs.StringVar(&requestTarget, "request-target", "", "override the request-target form written on the wire (RFC 7230 §5.3): origin (path+query, the default), absolute (a full absolute-URI, as sent to a proxy, without needing a real one), authority (host:port; requires CONNECT), or asterisk (the literal \"*\"; requires OPTIONS)")
+	fs.StringVar(&proxyCACertPath, "proxy-cacert", "", "trust this PEM CA certificate file for the proxy's own TLS certificate (an https:// --proxy), independently of the origin server's verification")
+	fs.BoolVar(&proxyInsecure, "proxy-insecure", false, "skip verification of the proxy's own TLS certificate (an https:// --proxy), independently of the origin server's verification")
+	fs.StringVar(&maxBodySize, "max-body-size", "10MB", "cap response body reads to this many bytes; 0 for unlimited")
+	fs.StringVar(&maxResponseHeaderSize, "max-response-header-size", "1MB", "abort the response if its status line and headers together exceed this many bytes, guarding against a server that never terminates its status line; 0 uses net/http's own built-in default (10MB)")
+	fs.BoolVar(&strict, "strict", false, "treat validation warnings as fatal errors")
+	fs.BoolVar(&insecure, "insecure", false, "suppress warnings about sending credentials over plain HTTP")
+	fs.StringVar(&colorMode, "color", "auto", "when to syntax-highlight a JSON response body: auto (only when stdout is a terminal), always, or never; the NO_COLOR environment variable, if set to any value, always disables it")
+	fs.StringVar(&themeName, "theme", "default", "color theme for --color output: "+strings.Join(theme.Names(), ", "))
+	fs.BoolVar(&noAutoHeaders, "no-auto-headers", false, "don't auto-add missing Host/Content-Length headers; warn instead of mutating the request")
+	fs.Var(&skipCheck, "skip-check", "disable a named validation check (e.g. host-required); may be repeated")
+	fs.BoolVar(&verbose, "verbose", false, "print the request/response wire details and TLS info to stderr, curl -v style")
+	fs.BoolVar(&verboseShowSecrets, "verbose-show-secrets", false, "don't redact Authorization/Cookie values in --verbose output")
+	fs.BoolVar(&list, "list", false, "print the names/indices of every request in the file and exit")
+	fs.StringVar(&selectFlag, "select", "", "send only the request matching this name (case-insensitive) or 1-based index")
+	fs.StringVar(&timeout, "timeout", "30s", "fail the request if it hasn't completed after this long")
+	fs.StringVar(&timeoutConnect, "timeout-connect", "0", "fail with a distinct \"connect timeout\" error if DNS resolution and dialing alone take longer than this; 0 leaves connect governed by --timeout like everything else")
+	fs.BoolVar(&failEarly, "fail-early", false, "resolve the host before dialing, failing immediately with a clear \"could not resolve host\" error on an obvious typo instead of a generic connection error later")
+	fs.StringVar(&failEarlyTimeout, "fail-early-timeout", "2s", "how long --fail-early's DNS lookup gets before giving up and letting the connection attempt proceed normally")
+	fs.StringVar(&maxTime, "max-time", "0", "hard wall-clock deadline on the entire send+receive; 0 for unlimited, unlike --timeout this can't be extended by a server that trickles bytes")
+	fs.StringVar(&configPath, "config", "", "load default flag values and per-host overrides from this config file")
+	fs.BoolVar(&urlEncode, "url-encode", false, "percent-encode unsafe characters left in the request path/query instead of only warning about them")
+	fs.BoolVar(&allowBodyOnGet, "allow-body-on-get", false, "suppress the warning about sending a body on GET/HEAD/DELETE, for APIs (e.g. Elasticsearch's GET _search) that legitimately require one; the body is sent either way")
+	fs.Var(&formFields, "form", "add a multipart/form-data field (name=value); may be repeated")
+	fs.Var(&formFiles, "form-file", "add a multipart/form-data file field (name=@path); may be repeated")
+	fs.Var(&dataFields, "data", "add an application/x-www-form-urlencoded field (name=value); may be repeated")
+	fs.StringVar(&harPath, "trace", "", "write a HAR 1.2 log of every request/response to this file")
+	fs.StringVar(&recordPath, "record", "", "save the last request/response exchange to this file, for --replay to reproduce later without a network round trip")
+	fs.StringVar(&replayPath, "replay", "", "print the request/response exchange saved by --record, reconstructed from this file, without touching the network")
+	fs.StringVar(&logFilePath, "log-file", "", "write structured JSON-lines request/response/redirect events to this file, keeping stdout limited to the response; complements --verbose's human-readable stderr output")
+	fs.Var(&resolveOverrides, "resolve", "dial addr instead of host:port, keeping the original Host header and TLS SNI (host:port:addr); may be repeated")
+	fs.Var(&connectToOverrides, "connect-to", "dial connecthost:connectport instead of host:port, keeping the original Host header and TLS SNI (host:port:connecthost:connectport); may be repeated")
+	fs.StringVar(&maxHeaderSize, "max-header-size", "8KB", "warn when a single header value exceeds this size")
+	fs.StringVar(&maxHeadersSize, "max-headers-size", "16KB", "warn when the combined size of all headers exceeds this size")
+	fs.StringVar(&maxRequestLineSize, "max-request-line-size", "8000", "warn when the request line (method, target, protocol) exceeds this size")
+	fs.StringVar(&headerCase, "header-case", "preserve", "header name casing on the wire: preserve (as written in the file), canonical (Content-Type), or lower (HTTP/2 style)")
+	fs.BoolVar(&bodyOnly, "body-only", false, "print only the response body, suppressing the status line, headers, and any --timing/--color output")
+	fs.BoolVar(&stream, "stream", false, "flush the status line, headers, and body to stdout as they arrive instead of buffering the whole response; bypasses --max-body-size")
+	fs.BoolVar(&fail, "fail", false, "exit non-zero, without printing the response, when the server returns a 4xx or 5xx status")
+	fs.BoolVar(&failWithBody, "fail-with-body", false, "like --fail, but prints the response first, so an API's error body is still visible in CI logs; mutually exclusive with --fail")
+	fs.StringVar(&maxResponseTime, "max-response-time", "0", "exit non-zero, after printing the response, if --timing's round-trip duration exceeds this; 0 disables the check. Combine with --fail for a complete cron/uptime health check")
+	fs.BoolVar(&repl, "repl", false, "after preparing the request, drop into an interactive prompt to tweak and resend it (set header/method/body, send, show, save <file>, quit); reads commands from stdin")
+	fs.BoolVar(&prompt, "prompt", false, "when a \"{{name}}\" placeholder isn't captured or in the environment, ask for a value on stdin instead of failing; hides input for a name containing token/password/secret; collected values apply for the rest of the run")
+	fs.BoolVar(&prompt, "interactive", false, "shorthand for --prompt")
+	fs.BoolVar(&websocket, "websocket", false, "perform the request as a WebSocket handshake (RFC 6455): validate the server's 101 response and Sec-WebSocket-Accept, then send each stdin line as a text frame and print frames received from the server until stdin hits EOF")
+	fs.StringVar(&sni, "sni", "", "TLS server name to send in the ClientHello, independent of the Host header and URL; certificate verification then checks against this name instead of the URL hostname")
+	fs.BoolVar(&noBody, "no-body", false, "strip the request body and its Content-Length/Content-Type headers before sending")
+	fs.BoolVar(&bodyStdin, "body-stdin", false, "read the request body from stdin, streamed lazily rather than buffered, replacing whatever the file specifies; recomputes Content-Length as chunked transfer-encoding since stdin's length isn't known ahead of time; only affects the first request in the file")
+	fs.StringVar(&uploadFile, "upload-file", "", "read the request body from this file, streamed lazily rather than buffered so uploads larger than memory work, and recompute Content-Length from the file's size; only affects the first request in the file; mutually exclusive with --body-stdin")
+	fs.StringVar(&headersFile, "headers-file", "", "merge \"Name: value\" headers from this file into every request as defaults, letting a team share a common header set (auth, tracing) across many .http files; a request's own headers still override; {{var}} placeholders in the file are resolved the same as any other header")
+	fs.StringVar(&baselinePath, "baseline", "", "compare the response's status, headers, and body against a response file saved by --save-baseline, printing a unified diff and exiting non-zero if they differ; requires exactly one request")
+	fs.StringVar(&saveBaselinePath, "save-baseline", "", "save the response's status, headers, and body to this file for a later --baseline comparison, instead of comparing it; requires exactly one request")
+	fs.Var(&baselineIgnoreHeaders, "baseline-ignore-header", "ignore this header when comparing against --baseline (e.g. a timestamp or request ID that legitimately changes every run); may be repeated; Date is always ignored")
+	fs.StringVar(&openapiSpec, "openapi", "", "build the request from an OpenAPI 3.0 document (JSON only) instead of a .http file; use with --operation")
+	fs.StringVar(&openapiOperation, "operation", "", "the operationId to build a request for; requires --openapi")
+	fs.Var(&openapiParams, "openapi-param", "fill an OpenAPI path/query parameter (name=value); may be repeated")
+	fs.StringVar(&extractPath, "extract", "", "print only the value at this path within a JSON response body (e.g. $.data.token or $.items[0].id), instead of the full response; exits non-zero if the path doesn't resolve")
+	fs.BoolVar(&continueOnError, "continue-on-error", false, "keep sending the remaining requests in a batch after one fails, instead of aborting immediately; exits non-zero with a summary if any failed")
+	fs.StringVar(&environment, "environment", "", "select a named profile from http-client.env.json (next to the first .http file) whose variables resolve {{var}} placeholders")
+	fs.StringVar(&outputPath, "output", "", "write the response body to this file instead of printing it")
+	fs.StringVar(&savePath, "save", "", "write the processed request (variables resolved, headers injected) to this file in .http format; doesn't imply --no-send, combine the two to snapshot without sending")
+	fs.BoolVar(&watch, "watch", false, "watch the given .http file and re-parse/re-send its requests on every change, clearing the terminal between runs, until interrupted")
+	fs.BoolVar(&resume, "resume", false, "with --output, resume a partial download by sending Range: bytes=<existing size>- and appending the response; restarts from scratch if the server ignores the range and returns 200")
+	fs.BoolVar(&lint, "lint", false, "statically validate every request in the given .http files without sending anything, printing a per-file report; exits non-zero if any errors (or, with --strict, warnings) are found")
+	fs.StringVar(&lintFormat, "lint-format", "text", "report format for --lint: text or json")
+	fs.StringVar(&format, "format", "auto", "input format for file arguments: auto (detect by extension), http, or json; json unmarshals a {method,url,version,headers,body} document into a request, skipping the .http text parser")
+	fs.StringVar(&signScheme, "sign", "", "sign the request before sending: aws-sigv4 (needs --aws-region and --aws-service; reads credentials from AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, and optionally AWS_SESSION_TOKEN)")
+	fs.StringVar(&awsRegion, "aws-region", "", "AWS region for --sign aws-sigv4's credential scope, e.g. us-east-1")
+	fs.StringVar(&awsService, "aws-service", "", "AWS service name for --sign aws-sigv4's credential scope, e.g. execute-api")
+	fs.BoolVar(&raw, "raw", false, "send the file's bytes over the wire completely unchanged, with no parsing, validation, or header injection; requires --target since there's no URL to derive a host from")
+	fs.StringVar(&target, "target", "", "host:port to connect to for --raw")
+	fs.BoolVar(&head, "head", false, "force the request method to HEAD, overriding the file, and print only the response status line and headers with no body, mirroring curl -I")
+	fs.BoolVar(&head, "I", false, "shorthand for --head")
+	fs.BoolVar(&discover, "discover", false, "force the request method to OPTIONS, overriding the file, and print the response's Allow and Access-Control-Allow-* headers as friendly comma-separated lists after the normal response")
+	fs.BoolVar(&effectiveURL, "effective-url", false, "print the URL that actually produced the response after the normal response, curl's %{url_effective}; differs from the requested URL when redirects were followed")
+	fs.Int64Var(&seed, "seed", 0, "seed the {{uuid}}/{{randInt}} template placeholders for reproducible output; unset, they're seeded from the current time")
+	fs.Var(&expectStatus, "expect-status", "assert the response status code equals this value; may be repeated, and combines with any per-block \"# @expect\" directives")
+	fs.Var(&expectHeader, "expect-header", "assert a response header matches \"Name: value\" (value is matched as a regular expression if it compiles as one, otherwise as a substring); may be repeated")
+	fs.Var(&expectBodyContains, "expect-body-contains", "assert the response body matches this value (regular expression if it compiles as one, otherwise a substring); may be repeated")
+	fs.StringVar(&methodOverride, "method", "", "the request method for a bare URL positional argument, or an override for a .http file's method; defaults to GET for a bare URL")
+	fs.StringVar(&methodOverride, "X", "", "shorthand for --method")
+	fs.Var(&headerFlags, "header", "add a header (\"Name: value\") to a bare URL positional argument; may be repeated")
+	fs.Var(&headerFlags, "H", "shorthand for --header")
+	fs.StringVar(&jsonBody, "json-body", "", "set a bare URL positional argument's body to this JSON and its Content-Type to application/json; the value must be well-formed JSON")
+	fs.StringVar(&contentTypeAlias, "content-type", "", "set a bare URL positional argument's Content-Type, expanding a short alias (json, form, xml, text) to its full MIME type, or used as-is otherwise")
+	fs.StringVar(&contentTypeAlias, "type", "", "shorthand for --content-type")
+	fs.IntVar(&parallel, "parallel", 0, "send up to N independent requests concurrently instead of one at a time, printing each one's output in input order once all complete; 0 or 1 sends sequentially; incompatible with --cookie-jar and --stream")
+	fs.BoolVar(&ntlmAuth, "ntlm", false, "authenticate with NTLM, performing the Type 1/2/3 handshake over the same connection before sending the real request; requires --user")
+	fs.BoolVar(&digestAuth, "digest", false, "authenticate with HTTP Digest (RFC 7616), sending an unauthenticated probe request, computing a response hash from the resulting 401 challenge (MD5 or SHA-256, with or without qop=auth), and resending once; requires --user; mutually exclusive with --ntlm")
+	fs.Var(&pins, "pin", "fail the connection unless the server's certificate public key hashes to this pin (sha256//<base64>); may be repeated, any match passes")
+	fs.Var(&noRedirectTo, "no-redirect-to", "abort with an error if a redirect points at this host, guarding against an open-redirect regression or an accidental hop to a disallowed host; may be repeated")
+	fs.IntVar(&maxRedirects, "max-redirects", 0, "stop following redirects after this many hops; 0 uses a built-in default of 10")
+	fs.BoolVar(&locationTrusted, "location-trusted", false, "keep sending Authorization and Cookie headers across a redirect to a different host, instead of dropping them")
+	fs.StringVar(&user, "user", "", "credentials for --ntlm (domain\\user:pass) or --digest (user:pass)")
+	fs.IntVar(&maxHeaderLines, "max-header-lines", 0, "cap the number of header lines read per request in a .http file, guarding against a malformed input; 0 uses a built-in default")
+	fs.StringVar(&printSpec, "print", "hb", "which parts of the request/response to print: H request headers, B request body, h response headers, b response body, in any combination; subsumes --body-only and --head")
+	fs.StringVar(&compress, "compress", "", "gzip-compress the request body before sending, setting Content-Encoding: gzip and recomputing Content-Length; currently only \"gzip\" is supported")
+	fs.BoolVar(&autoScheme, "auto-scheme", false, "if the server signals a scheme mismatch (e.g. an https request answered by a plain http server, or vice versa), retry the request once with the corrected scheme instead of failing")
+	fs.StringVar(&userAgent, "user-agent", "", "User-Agent header to send, overriding the file and any default")
+	fs.BoolVar(&noDefaultHeaders, "no-default-headers", false, "don't inject a default User-Agent header when the request doesn't already set one")
+	fs.IntVar(&trimResponse, "trim-response", 0, "print only the first N bytes of the response body, followed by a \"[... N more bytes]\" marker; 0 prints the whole body; the full body is still written to --output; mutually exclusive with --trim-lines")
+	fs.IntVar(&trimLines, "trim-lines", 0, "like --trim-response, but limits the printed body to its first N lines instead of N bytes; mutually exclusive with --trim-response")
+	fs.BoolVar(&sortHeaders, "sort-headers", false, "align printed header colons into a column for readability; headers are already alphabetized either way, and the bytes sent on the wire are unaffected")
+	fs.StringVar(&hostOverride, "host", "", "override the Host header (and, unless --sni is also given, the TLS SNI) to this value while still connecting to the request URL's own host; sugar for virtual-host testing against a raw IP without hand-editing both the URL and the header")
+	fs.StringVar(&hashAlgo, "hash", "", "print a hash of the response body (sha256, sha1, or md5) after the response, for comparing large payloads cheaply; defaults to sha256 when only --expect-hash is given")
+	fs.StringVar(&expectHash, "expect-hash", "", "assert the response body's hash (algorithm from --hash, default sha256) equals this hex digest, exiting non-zero on mismatch; handy in CI to verify a download without storing the whole file")
+	fs.IntVar(&retry, "retry", 0, "retry a response whose status is in --retry-on-status (default 429, 503) up to N additional times")
+	fs.StringVar(&retryDelay, "retry-delay", "1s", "how long to wait between --retry attempts")
+	fs.StringVar(&retryOnStatus, "retry-on-status", "", "comma-separated status codes that trigger a --retry attempt (e.g. 429,502,503,504); defaults to 429,503")
+	fs.StringVar(&summary, "summary", "", "after each send, write a single parseable \"status=... bytes=... time=... redirects=... host=...\" line for scripting: stdout, stderr, or \"\" to disable")
+
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if openapiSpec == "" && openapiOperation != "" {
+		fmt.Fprintln(stderr, "http: --operation requires --openapi")
+		return 2
+	}
+	if openapiSpec != "" && openapiOperation == "" {
+		fmt.Fprintln(stderr, "http: --openapi requires --operation")
+		return 2
+	}
+
+	if openapiSpec == "" && replayPath == "" && fs.NArg() < 1 {
+		fmt.Fprintln(stderr, "usage: http [flags] <file.http>...")
+		return 2
+	}
+	if openapiSpec != "" && fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "http: --openapi builds its own request; it doesn't take a .http file")
+		return 2
+	}
+	if replayPath != "" && fs.NArg() > 0 {
+		fmt.Fprintln(stderr, "http: --replay reconstructs its own request/response; it doesn't take a .http file")
+		return 2
+	}
+
+	if forceHTTP && forceHTTPS {
+		fmt.Fprintln(stderr, "http: --http and --https are mutually exclusive")
+		return 2
+	}
+
+	if head && bodyOnly {
+		fmt.Fprintln(stderr, "http: --head and --body-only are mutually exclusive")
+		return 2
+	}
+
+	if discover && head {
+		fmt.Fprintln(stderr, "http: --discover and --head are mutually exclusive")
+		return 2
+	}
+
+	if fail && failWithBody {
+		fmt.Fprintln(stderr, "http: --fail and --fail-with-body are mutually exclusive")
+		return 2
+	}
+
+	if parallel > 1 && cookieJarPath != "" {
+		fmt.Fprintln(stderr, "http: --parallel and --cookie-jar are mutually exclusive; a shared cookie jar can't be synchronized across concurrent requests")
+		return 2
+	}
+
+	if parallel > 1 && stream {
+		fmt.Fprintln(stderr, "http: --parallel and --stream are mutually exclusive")
+		return 2
+	}
+
+	var globalExpectations []models.Expectation
+	for _, raw := range expectStatus {
+		exp, err := parseExpectStatusFlag(raw)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		globalExpectations = append(globalExpectations, exp)
+	}
+	for _, raw := range expectHeader {
+		exp, err := parseExpectHeaderFlag(raw)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		globalExpectations = append(globalExpectations, exp)
+	}
+	for _, raw := range expectBodyContains {
+		globalExpectations = append(globalExpectations, models.Expectation{Kind: models.ExpectBodyContains, Value: raw})
+	}
+
+	switch headerCase {
+	case "preserve", "canonical", "lower":
+	default:
+		fmt.Fprintf(stderr, "http: invalid --header-case %q, expected preserve, canonical, or lower\n", headerCase)
+		return 2
+	}
+
+	switch colorMode {
+	case "auto", "always", "never":
+	default:
+		fmt.Fprintf(stderr, "http: invalid --color %q, expected auto, always, or never\n", colorMode)
+		return 2
+	}
+
+	switch summary {
+	case "", "stdout", "stderr":
+	default:
+		fmt.Fprintf(stderr, "http: invalid --summary %q, expected stdout or stderr\n", summary)
+		return 2
+	}
+
+	switch requestTarget {
+	case "", "origin", "absolute", "authority", "asterisk":
+	default:
+		fmt.Fprintf(stderr, "http: invalid --request-target %q, expected origin, absolute, authority, or asterisk\n", requestTarget)
+		return 2
+	}
+
+	if requestTarget != "" && proxy != "" {
+		fmt.Fprintln(stderr, "http: --request-target and --proxy are mutually exclusive; --proxy already determines the request-target form")
+		return 2
+	}
+
+	if expectHash != "" && hashAlgo == "" {
+		hashAlgo = "sha256"
+	}
+	switch hashAlgo {
+	case "", "sha256", "sha1", "md5":
+	default:
+		fmt.Fprintf(stderr, "http: invalid --hash %q, expected sha256, sha1, or md5\n", hashAlgo)
+		return 2
+	}
+
+	if _, ok := theme.Lookup(themeName); !ok {
+		fmt.Fprintf(stderr, "http: invalid --theme %q, expected one of: %s\n", themeName, strings.Join(theme.Names(), ", "))
+		return 2
+	}
+
+	if repl && noSend {
+		fmt.Fprintln(stderr, "http: --repl and --no-send are mutually exclusive")
+		return 2
+	}
+
+	if validateOnly && noSend {
+		fmt.Fprintln(stderr, "http: --validate-only and --no-send are mutually exclusive")
+		return 2
+	}
+
+	if validateOnly && repl {
+		fmt.Fprintln(stderr, "http: --validate-only and --repl are mutually exclusive")
+		return 2
+	}
+
+	if trimResponse > 0 && trimLines > 0 {
+		fmt.Fprintln(stderr, "http: --trim-response and --trim-lines are mutually exclusive")
+		return 2
+	}
+
+	if baselinePath != "" && saveBaselinePath != "" {
+		fmt.Fprintln(stderr, "http: --baseline and --save-baseline are mutually exclusive; capture a baseline first, then compare against it on a later run")
+		return 2
+	}
+
+	if bodyStdin && repl {
+		fmt.Fprintln(stderr, "http: --body-stdin and --repl are mutually exclusive; both read from stdin")
+		return 2
+	}
+
+	if websocket && repl {
+		fmt.Fprintln(stderr, "http: --websocket and --repl are mutually exclusive")
+		return 2
+	}
+
+	if websocket && noSend {
+		fmt.Fprintln(stderr, "http: --websocket and --no-send are mutually exclusive")
+		return 2
+	}
+
+	if websocket && bodyStdin {
+		fmt.Fprintln(stderr, "http: --websocket and --body-stdin are mutually exclusive; both read from stdin")
+		return 2
+	}
+
+	if websocket && raw {
+		fmt.Fprintln(stderr, "http: --websocket and --raw are mutually exclusive")
+		return 2
+	}
+
+	if prompt && bodyStdin {
+		fmt.Fprintln(stderr, "http: --prompt and --body-stdin are mutually exclusive; both read from stdin")
+		return 2
+	}
+
+	if prompt && repl {
+		fmt.Fprintln(stderr, "http: --prompt and --repl are mutually exclusive; both read from stdin")
+		return 2
+	}
+
+	if bodyStdin && uploadFile != "" {
+		fmt.Fprintln(stderr, "http: --body-stdin and --upload-file are mutually exclusive")
+		return 2
+	}
+
+	if resume && outputPath == "" {
+		fmt.Fprintln(stderr, "http: --resume requires --output")
+		return 2
+	}
+
+	if lint && openapiSpec != "" {
+		fmt.Fprintln(stderr, "http: --lint validates .http files; it doesn't apply to --openapi")
+		return 2
+	}
+	switch lintFormat {
+	case "text", "json":
+	default:
+		fmt.Fprintf(stderr, "http: invalid --lint-format %q, expected text or json\n", lintFormat)
+		return 2
+	}
+	switch format {
+	case "auto", "http", "json":
+	default:
+		fmt.Fprintf(stderr, "http: invalid --format %q, expected auto, http, or json\n", format)
+		return 2
+	}
+
+	if replayPath != "" {
+		f, err := os.Open(replayPath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		defer f.Close()
+		exchange, err := export.ReadExchange(f)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		req, err := exchange.ToRequest()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		resp, err := exchange.ToResponse()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		printRequestAndResponse(stdout, req, resp, defaultPrintParts, headerCase, false, "", false, 0, 0, false)
+		return 0
+	}
+
+	if raw {
+		if openapiSpec != "" {
+			fmt.Fprintln(stderr, "http: --raw and --openapi are mutually exclusive")
+			return 2
+		}
+		if target == "" {
+			fmt.Fprintln(stderr, "http: --raw requires --target host:port")
+			return 2
+		}
+		if fs.NArg() != 1 {
+			fmt.Fprintln(stderr, "http: --raw takes exactly one file of request bytes to send verbatim")
+			return 2
+		}
+		rawTimeout, err := time.ParseDuration(timeout)
+		if err != nil {
+			fmt.Fprintf(stderr, "http: invalid --timeout: %v\n", err)
+			return 2
+		}
+		return runRaw(fs.Args()[0], target, rawTimeout, stdout, stderr)
+	}
+
+	form := formInput{fields: formFields, files: formFiles, data: dataFields}
+	if len(form.data) > 0 && (len(form.fields) > 0 || len(form.files) > 0) {
+		fmt.Fprintln(stderr, "http: --form/--form-file and --data are mutually exclusive")
+		return 2
+	}
+
+	explicitFlags := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicitFlags[f.Name] = true })
+
+	if explicitFlags["print"] && (explicitFlags["body-only"] || explicitFlags["head"] || explicitFlags["I"]) {
+		fmt.Fprintln(stderr, "http: --print and --body-only/--head are mutually exclusive")
+		return 2
+	}
+
+	parts := defaultPrintParts
+	switch {
+	case explicitFlags["print"]:
+		var err error
+		parts, err = parsePrintParts(printSpec)
+		if err != nil {
+			fmt.Fprintf(stderr, "http: %v\n", err)
+			return 2
+		}
+	case bodyOnly:
+		parts = printParts{responseBody: true}
+	case head:
+		parts = printParts{responseHeaders: true}
+	}
+
+	if explicitFlags["seed"] {
+		chain.SetSeed(seed)
+	}
+
+	maxBodyBytes, err := sizeutil.Parse(maxBodySize)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --max-body-size: %v\n", err)
+		return 2
+	}
+
+	maxResponseHeaderBytes, err := sizeutil.Parse(maxResponseHeaderSize)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --max-response-header-size: %v\n", err)
+		return 2
+	}
+
+	maxHeaderBytes, err := sizeutil.Parse(maxHeaderSize)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --max-header-size: %v\n", err)
+		return 2
+	}
+	maxHeadersBytes, err := sizeutil.Parse(maxHeadersSize)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --max-headers-size: %v\n", err)
+		return 2
+	}
+	maxRequestLineBytes, err := sizeutil.Parse(maxRequestLineSize)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --max-request-line-size: %v\n", err)
+		return 2
+	}
+
+	var requests []*models.Request
+	if openapiSpec != "" {
+		spec, err := openapi.LoadSpec(openapiSpec)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		req, err := openapi.BuildRequest(spec, openapiOperation, openapiParams.toMap())
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		requests = []*models.Request{req}
+	} else if fs.NArg() == 1 && looksLikeURL(fs.Arg(0)) {
+		req, err := quickRequest(fs.Arg(0), methodOverride, headerFlags, jsonBody, contentTypeAlias)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		requests = []*models.Request{req}
+	} else {
+		paths, err := expandFileArgs(fs.Args())
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		for _, path := range paths {
+			fileRequests, warnings, err := parseFile(path, maxHeaderLines, format)
+			for _, w := range warnings {
+				fmt.Fprintf(stderr, "warning: %s: %s\n", path, w)
+			}
+			if err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+			requests = append(requests, fileRequests...)
+		}
+	}
+	if len(requests) == 0 {
+		fmt.Fprintln(stderr, "http: no requests found in file")
+		return 1
+	}
+
+	if list {
+		for i, r := range requests {
+			fmt.Fprintf(stdout, "%d: %s\n", i+1, requestLabel(r))
+		}
+		return 0
+	}
+
+	// store holds every "{{name}}" placeholder value available before a
+	// single byte is sent: "@name = value" declarations from every
+	// block's preamble, applied file order so a later declaration
+	// overrides an earlier one, then (below) any --environment profile
+	// variables, which take precedence over a file's own defaults. It's
+	// built here, rather than down by sendOne, so the single-request
+	// paths (--validate-only, --no-send, --repeat, --repl) see declared
+	// variables too.
+	store := make(chain.Store)
+	for _, r := range requests {
+		for _, d := range r.Declarations {
+			value, err := store.Resolve(d.Value)
+			if err != nil {
+				fmt.Fprintf(stderr, "http: declaration %q: %v\n", d.Name, err)
+				return 1
+			}
+			store[d.Name] = value
+		}
+	}
+
+	requestsToRun := requests
+	if selectFlag != "" {
+		selected, err := selectRequest(requests, selectFlag)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		requestsToRun = []*models.Request{selected}
+	}
+
+	if (baselinePath != "" || saveBaselinePath != "") && len(requestsToRun) > 1 {
+		fmt.Fprintln(stderr, "http: --baseline and --save-baseline require exactly one request; use --select to narrow a multi-request file")
+		return 2
+	}
+
+	if bodyStdin {
+		if stdin == nil {
+			fmt.Fprintln(stderr, "http: --body-stdin requires a stdin pipe")
+			return 2
+		}
+		req := requestsToRun[0]
+		req.Body = ""
+		req.BodyReader = stdin
+		req.DeleteHeader("Content-Length")
+	}
+
+	if uploadFile != "" {
+		f, err := os.Open(uploadFile)
+		if err != nil {
+			fmt.Fprintf(stderr, "http: opening --upload-file: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+		info, err := f.Stat()
+		if err != nil {
+			fmt.Fprintf(stderr, "http: stat'ing --upload-file: %v\n", err)
+			return 1
+		}
+		req := requestsToRun[0]
+		req.Body = ""
+		req.BodyReader = f
+		req.BodyLength = info.Size()
+		req.DeleteHeader("Content-Length")
+	}
+
+	if headersFile != "" {
+		headers, err := loadHeadersFile(headersFile)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		for _, req := range requestsToRun {
+			applyHeaderDefaults(req, headers)
+		}
+	}
+
+	if hostOverride != "" {
+		for _, req := range requestsToRun {
+			req.SetHeader("Host", hostOverride)
+		}
+		if sni == "" {
+			sni = hostOverride
+		}
+	}
+
+	cfgPath := configPath
+	if cfgPath == "" {
+		if dir, err := os.UserConfigDir(); err == nil {
+			if candidate := filepath.Join(dir, "http", "config.toml"); fileExists(candidate) {
+				cfgPath = candidate
+			}
+		}
+	}
+	var cfg *config.Config
+	if cfgPath != "" {
+		cfg, err = config.Load(cfgPath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	// Host matching uses the first request to run; a file chaining
+	// several "###" blocks is expected to target one service.
+	host := ""
+	if requestsToRun[0].URL != nil {
+		host = requestsToRun[0].URL.Host
+	}
+	resolved := cfg.Resolve(host)
+
+	if !explicitFlags["insecure"] && resolved.Insecure {
+		insecure = true
+	}
+	if !explicitFlags["proxy"] && resolved.Proxy != "" {
+		proxy = resolved.Proxy
+	}
+	if !explicitFlags["timeout"] && resolved.Timeout > 0 {
+		timeout = resolved.Timeout.String()
+	}
+
+	var proxyURL *url.URL
+	if proxy != "" {
+		parsed, err := url.Parse(proxy)
+		if err != nil {
+			fmt.Fprintf(stderr, "http: invalid --proxy URL: %v\n", err)
+			return 1
+		}
+		proxyURL = parsed
+	}
+	if (proxyCACertPath != "" || proxyInsecure) && (proxyURL == nil || proxyURL.Scheme != "https") {
+		fmt.Fprintln(stderr, "http: --proxy-cacert and --proxy-insecure require an https:// --proxy")
+		return 2
+	}
+	var proxyCACert *x509.CertPool
+	if proxyCACertPath != "" {
+		pem, err := os.ReadFile(proxyCACertPath)
+		if err != nil {
+			fmt.Fprintf(stderr, "http: --proxy-cacert: %v\n", err)
+			return 1
+		}
+		proxyCACert = x509.NewCertPool()
+		if !proxyCACert.AppendCertsFromPEM(pem) {
+			fmt.Fprintf(stderr, "http: --proxy-cacert: %s contains no usable certificates\n", proxyCACertPath)
+			return 2
+		}
+	}
+
+	timeoutDuration, err := time.ParseDuration(timeout)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --timeout: %v\n", err)
+		return 1
+	}
+
+	timeoutConnectDuration, err := time.ParseDuration(timeoutConnect)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --timeout-connect: %v\n", err)
+		return 1
+	}
+
+	maxTimeDuration, err := time.ParseDuration(maxTime)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --max-time: %v\n", err)
+		return 1
+	}
+
+	failEarlyTimeoutDuration, err := time.ParseDuration(failEarlyTimeout)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --fail-early-timeout: %v\n", err)
+		return 1
+	}
+
+	retryDelayDuration, err := time.ParseDuration(retryDelay)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --retry-delay: %v\n", err)
+		return 1
+	}
+
+	maxResponseTimeDuration, err := time.ParseDuration(maxResponseTime)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --max-response-time: %v\n", err)
+		return 1
+	}
+
+	retryStatuses, err := parseRetryStatuses(retryOnStatus)
+	if err != nil {
+		fmt.Fprintf(stderr, "http: invalid --retry-on-status: %v\n", err)
+		return 2
+	}
+	if retryStatuses == nil {
+		retryStatuses = defaultRetryStatuses
+	}
+
+	resolve, err := resolveOverrides.toMap()
+	if err != nil {
+		fmt.Fprintf(stderr, "http: %v\n", err)
+		return 1
+	}
+
+	connectTo, err := connectToOverrides.toMap()
+	if err != nil {
+		fmt.Fprintf(stderr, "http: %v\n", err)
+		return 1
+	}
+
+	var cache *reqcache.Cache
+	if cacheDir != "" {
+		cache = reqcache.Open(cacheDir)
+	}
+
+	var jar *cookiejar.Jar
+	if cookieJarPath != "" {
+		jar, err = cookiejar.Load(cookieJarPath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+
+	vopts := validate.ValidateOptions{
+		NoSecure:            insecure,
+		NoAutoHeaders:       noAutoHeaders,
+		DisabledChecks:      skipCheck,
+		Strict:              strict,
+		URLEncode:           urlEncode,
+		AllowBodyOnGet:      allowBodyOnGet,
+		MaxHeaderValueBytes: maxHeaderBytes,
+		MaxTotalHeaderBytes: maxHeadersBytes,
+		MaxRequestLineBytes: maxRequestLineBytes,
+	}
+
+	if lint {
+		return runLint(fs.Args(), vopts, strict, lintFormat, maxHeaderLines, format, stdout, stderr)
+	}
+
+	if watch {
+		if openapiSpec != "" || (fs.NArg() == 1 && looksLikeURL(fs.Arg(0))) {
+			fmt.Fprintln(stderr, "http: --watch requires a .http file, not --openapi or a bare URL")
+			return 2
+		}
+		if fs.NArg() != 1 {
+			fmt.Fprintln(stderr, "http: --watch requires exactly one file")
+			return 2
+		}
+		clientOpts := client.Options{Proxy: proxyURL, ProxyCACert: proxyCACert, ProxyInsecureSkipVerify: proxyInsecure, RequestTargetForm: requestTarget, MaxBodySize: maxBodyBytes, MaxResponseHeaderBytes: maxResponseHeaderBytes, Timeout: timeoutDuration, TimeoutConnect: timeoutConnectDuration, MaxTime: maxTimeDuration, FailEarly: failEarly, FailEarlyTimeout: failEarlyTimeoutDuration, Resolve: resolve, ConnectTo: connectTo, HeaderCase: headerCase, SNI: sni, Pins: pins, MaxRedirects: maxRedirects, LocationTrusted: locationTrusted, DisallowedRedirectHosts: noRedirectTo}
+		stop := make(chan struct{})
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-sig
+			close(stop)
+		}()
+		runWatch(fs.Args()[0], watchOptions{
+			maxHeaderLines:   maxHeaderLines,
+			format:           format,
+			query:            query,
+			forceHTTP:        forceHTTP,
+			forceHTTPS:       forceHTTPS,
+			head:             head,
+			discover:         discover,
+			noBody:           noBody,
+			form:             form,
+			vopts:            vopts,
+			compress:         compress,
+			userAgent:        userAgent,
+			requestTarget:    requestTarget,
+			noDefaultHeaders: noDefaultHeaders,
+			headerCase:       headerCase,
+			clientOpts:       clientOpts,
+		}, stdout, stderr, stop)
+		return 0
+	}
+
+	var signer sign.Signer
+	switch signScheme {
+	case "":
+	case "aws-sigv4":
+		if awsRegion == "" || awsService == "" {
+			fmt.Fprintln(stderr, "http: --sign aws-sigv4 requires --aws-region and --aws-service")
+			return 2
+		}
+		accessKeyID, secretAccessKey := os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY")
+		if accessKeyID == "" || secretAccessKey == "" {
+			fmt.Fprintln(stderr, "http: --sign aws-sigv4 requires AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY in the environment")
+			return 2
+		}
+		signer = sign.AWSSigV4{
+			AccessKeyID:     accessKeyID,
+			SecretAccessKey: secretAccessKey,
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			Region:          awsRegion,
+			Service:         awsService,
+		}
+	default:
+		fmt.Fprintf(stderr, "http: invalid --sign %q, expected aws-sigv4\n", signScheme)
+		return 2
+	}
+
+	for _, p := range pins {
+		if !strings.HasPrefix(p, "sha256//") {
+			fmt.Fprintf(stderr, "http: invalid --pin %q, expected sha256//<base64>\n", p)
+			return 2
+		}
+	}
+
+	if ntlmAuth && digestAuth {
+		fmt.Fprintln(stderr, "http: --ntlm and --digest are mutually exclusive")
+		return 2
+	}
+
+	var ntlmCreds *client.NTLMCredentials
+	var digestCreds *client.DigestCredentials
+	switch {
+	case ntlmAuth && user == "":
+		fmt.Fprintln(stderr, "http: --ntlm requires --user domain\\user:pass")
+		return 2
+	case ntlmAuth:
+		domain, username, password, err := parseNTLMUser(user)
+		if err != nil {
+			fmt.Fprintln(stderr, "http:", err)
+			return 2
+		}
+		ntlmCreds = &client.NTLMCredentials{Domain: domain, User: username, Password: password}
+	case digestAuth && user == "":
+		fmt.Fprintln(stderr, "http: --digest requires --user user:pass")
+		return 2
+	case digestAuth:
+		username, password, err := parseDigestUser(user)
+		if err != nil {
+			fmt.Fprintln(stderr, "http:", err)
+			return 2
+		}
+		digestCreds = &client.DigestCredentials{User: username, Password: password}
+	case user != "":
+		fmt.Fprintln(stderr, "http: --user requires --ntlm or --digest")
+		return 2
+	}
+
+	// validateOnly, noSend and --repeat operate on a single request;
+	// chaining across multiple "###" blocks only makes sense when
+	// actually sending and reading each response in turn, below.
+	if validateOnly {
+		req := requestsToRun[0]
+		warnings, err := prepareRequest(req, store, query, forceHTTP, forceHTTPS, head, discover, noBody, jar, cache, form, vopts, compress, userAgent, requestTarget, noDefaultHeaders, prompt, safeStdin, stderr)
+		if err != nil {
+			fmt.Fprintf(stdout, "error: %v\n", err)
+			return 1
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(stdout, "warning: %s\n", w)
+		}
+		if len(warnings) == 0 {
+			fmt.Fprintln(stdout, "ok")
+		}
+		return 0
+	}
+
+	if noSend {
+		req := requestsToRun[0]
+		warnings, err := prepareRequest(req, store, query, forceHTTP, forceHTTPS, head, discover, noBody, jar, cache, form, vopts, compress, userAgent, requestTarget, noDefaultHeaders, prompt, safeStdin, stderr)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(stderr, "warning: %s\n", w)
+		}
+		if savePath != "" {
+			if err := saveRequest(savePath, req); err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+		}
+		render.Request(stdout, req, proxyURL != nil || requestTarget == "absolute", headerCase)
+		return 0
+	}
+
+	// interruptCtx is canceled on SIGINT/SIGTERM, so a Ctrl-C during a
+	// long streaming response or batch run aborts the in-flight request
+	// (closing its connection) instead of either running to completion
+	// or killing the process abruptly mid-write. stopInterrupt restores
+	// the default signal behavior once sending is done, so a second
+	// Ctrl-C after a clean exit doesn't linger with a handler installed.
+	interruptCtx, stopInterrupt := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopInterrupt()
+
+	opts := client.Options{Proxy: proxyURL, ProxyCACert: proxyCACert, ProxyInsecureSkipVerify: proxyInsecure, RequestTargetForm: requestTarget, MaxBodySize: maxBodyBytes, MaxResponseHeaderBytes: maxResponseHeaderBytes, Timeout: timeoutDuration, TimeoutConnect: timeoutConnectDuration, MaxTime: maxTimeDuration, FailEarly: failEarly, FailEarlyTimeout: failEarlyTimeoutDuration, Resolve: resolve, ConnectTo: connectTo, HeaderCase: headerCase, SNI: sni, NTLM: ntlmCreds, Digest: digestCreds, Pins: pins, MaxRedirects: maxRedirects, LocationTrusted: locationTrusted, DisallowedRedirectHosts: noRedirectTo, Context: interruptCtx}
+	if stream {
+		opts.Stream = stdout
+	}
+	if verbose {
+		opts.Trace = stderr
+		opts.TraceShowSecrets = verboseShowSecrets
+	}
+
+	if repeat > 0 {
+		req := requestsToRun[0]
+		warnings, err := prepareRequest(req, store, query, forceHTTP, forceHTTPS, head, discover, noBody, jar, cache, form, vopts, compress, userAgent, requestTarget, noDefaultHeaders, prompt, safeStdin, stderr)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(stderr, "warning: %s\n", w)
+		}
+		if savePath != "" {
+			if err := saveRequest(savePath, req); err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+		}
+		if !client.IdempotentMethods[req.Method] && !allowNonIdempotent {
+			fmt.Fprintf(stderr, "http: refusing to --repeat a non-idempotent %s request; pass --allow-non-idempotent to override\n", req.Method)
+			return 1
+		}
+		if signer != nil {
+			if err := signer.Sign(req); err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+		}
+		opts.PreferHTTP2 = req.IsHTTP2()
+		render.Stats(stdout, bench.Run(opts, req, repeat, concurrency))
+		return 0
+	}
+
+	if repl {
+		req := requestsToRun[0]
+		warnings, err := prepareRequest(req, store, query, forceHTTP, forceHTTPS, head, discover, noBody, jar, cache, form, vopts, compress, userAgent, requestTarget, noDefaultHeaders, prompt, safeStdin, stderr)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(stderr, "warning: %s\n", w)
+		}
+		if savePath != "" {
+			if err := saveRequest(savePath, req); err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+		}
+		if signer != nil {
+			if err := signer.Sign(req); err != nil {
+				fmt.Fprintln(stderr, err)
+				return 1
+			}
+		}
+		opts.PreferHTTP2 = req.IsHTTP2()
+		return runRepl(safeStdin, stdout, stderr, req, opts, vopts)
+	}
+
+	if websocket {
+		req := requestsToRun[0]
+		// ws/wss are equivalent to http/https for everything up to the
+		// upgrade itself (RFC 6455 §3): normalize the scheme in
+		// RawTarget (what resolveRequest re-parses req.URL from) so the
+		// normal validate/prepare pipeline, which only knows http and
+		// https, doesn't reject the request outright. secure is captured
+		// first since runWebSocket needs it to pick TLS vs. plain TCP
+		// after the scheme's been rewritten.
+		secure := strings.HasPrefix(req.RawTarget, "wss://") || strings.HasPrefix(req.RawTarget, "https://")
+		switch {
+		case strings.HasPrefix(req.RawTarget, "ws://"):
+			req.RawTarget = "http://" + strings.TrimPrefix(req.RawTarget, "ws://")
+		case strings.HasPrefix(req.RawTarget, "wss://"):
+			req.RawTarget = "https://" + strings.TrimPrefix(req.RawTarget, "wss://")
+		}
+		warnings, err := prepareRequest(req, store, query, forceHTTP, forceHTTPS, head, discover, noBody, jar, cache, form, vopts, compress, userAgent, requestTarget, noDefaultHeaders, prompt, safeStdin, stderr)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(stderr, "warning: %s\n", w)
+		}
+		return runWebSocket(safeStdin, stdout, stderr, req, opts, secure)
+	}
+
+	var trace *export.HAR
+	if harPath != "" {
+		trace = export.New()
+	}
+
+	var logger *eventlog.Logger
+	if logFilePath != "" {
+		f, err := os.Create(logFilePath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		defer f.Close()
+		logger = eventlog.New(f)
+	}
+
+	colorEnabled := false
+	switch colorMode {
+	case "always":
+		colorEnabled = true
+	case "auto":
+		colorEnabled = isTerminal(stdout)
+	}
+	if _, noColor := os.LookupEnv("NO_COLOR"); noColor {
+		colorEnabled = false
+	}
+
+	// failuresMu guards failures, since --parallel appends to it from
+	// multiple workers.
+	var failuresMu sync.Mutex
+
+	// recordFailure reports err for req: under --continue-on-error it's
+	// appended to failures and the batch moves on to the next request;
+	// otherwise it's printed immediately and the caller should abort.
+	var failures []string
+	recordFailure := func(req *models.Request, err error) {
+		if continueOnError {
+			failuresMu.Lock()
+			failures = append(failures, fmt.Sprintf("%s: %v", requestLabel(req), err))
+			failuresMu.Unlock()
+			return
+		}
+		fmt.Fprintln(stderr, err)
+	}
+
+	// fatalRequestError marks an error that always aborts the batch
+	// (e.g. failing to persist --cookie-jar to disk), regardless of
+	// --continue-on-error.
+	type fatalRequestError struct{ error }
+
+	// stateMu guards state shared by every request under --parallel:
+	// the cookie jar, --trace's HAR export, --cache-dir, and captured
+	// chain variables. It's a no-op source of contention when
+	// --parallel isn't in use.
+	var stateMu sync.Mutex
+
+	// recordedExchange holds the most recent request/response for
+	// --record to write out once the run finishes. --record is meant for
+	// recording a single request as a test fixture; given multiple, the
+	// last one sent wins.
+	var recordedExchange *export.Exchange
+
+	if environment != "" {
+		envDir := "."
+		if fs.NArg() > 0 {
+			envDir = filepath.Dir(fs.Args()[0])
+		}
+		envPath := filepath.Join(envDir, "http-client.env.json")
+		envs, err := envfile.Load(envPath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		vars, err := envs.Profile(environment)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+		for name, value := range vars {
+			store[name] = value
+		}
+	}
+
+	// sendOne runs req to completion, writing its output to out/errW
+	// instead of stdout/stderr directly so --parallel can buffer it and
+	// flush every request's output in input order once all complete.
+	// It returns the first error encountered; wrapping it in
+	// fatalRequestError means the whole batch must abort regardless of
+	// --continue-on-error.
+	sendOne := func(req *models.Request, out, errW io.Writer) error {
+		warnings, err := prepareRequest(req, store, query, forceHTTP, forceHTTPS, head, discover, noBody, jar, cache, form, vopts, compress, userAgent, requestTarget, noDefaultHeaders, prompt, safeStdin, stderr)
+		if err != nil {
+			return err
+		}
+		for _, w := range warnings {
+			fmt.Fprintf(errW, "warning: %s\n", w)
+		}
+
+		if savePath != "" {
+			if err := saveRequest(savePath, req); err != nil {
+				return err
+			}
+		}
+
+		if resume {
+			if info, err := os.Stat(outputPath); err == nil && info.Size() > 0 {
+				req.SetHeader("Range", fmt.Sprintf("bytes=%d-", info.Size()))
+			}
+		}
+
+		if signer != nil {
+			if err := signer.Sign(req); err != nil {
+				return err
+			}
+		}
+
+		reqOpts := opts
+		reqOpts.PreferHTTP2 = req.IsHTTP2()
+		if logger != nil {
+			logger.Log(eventlog.Event{Type: "request", Method: req.Method, URL: req.URL.String()})
+		}
+		startedAt := time.Now()
+		resp, err := sendWithRetry(client.New(reqOpts), req, autoScheme, retry, retryDelayDuration, retryStatuses)
+		if err != nil {
+			if logger != nil {
+				logger.Log(eventlog.Event{Type: "error", Method: req.Method, URL: req.URL.String(), DurationMS: time.Since(startedAt).Milliseconds(), Message: err.Error()})
+			}
+			return err
+		}
+		if logger != nil {
+			logger.Log(eventlog.Event{Type: "response", Method: req.Method, URL: req.URL.String(), StatusCode: resp.StatusCode, DurationMS: resp.Timing.Milliseconds()})
+		}
+		if trace != nil {
+			stateMu.Lock()
+			trace.Add(export.NewEntry(req, resp, startedAt))
+			stateMu.Unlock()
+		}
+		if recordPath != "" {
+			exchange := export.NewExchange(req, resp)
+			stateMu.Lock()
+			recordedExchange = &exchange
+			stateMu.Unlock()
+		}
+		if cache != nil && req.Method == http.MethodGet {
+			stateMu.Lock()
+			updateCache(cache, req, resp, errW)
+			stateMu.Unlock()
+		}
+		if resp.Truncated {
+			fmt.Fprintf(errW, "http: response body exceeded --max-body-size (%s), truncating\n", maxBodySize)
+		}
+		if resp.CharsetWarning != "" {
+			fmt.Fprintf(errW, "http: %s\n", resp.CharsetWarning)
+		}
+		for _, w := range resp.RedirectWarnings {
+			fmt.Fprintf(errW, "http: %s\n", w)
+			if logger != nil {
+				logger.Log(eventlog.Event{Type: "redirect", Method: req.Method, URL: req.URL.String(), Message: w})
+			}
+		}
+		if req.IsHTTP2() && !strings.HasPrefix(resp.Proto, "HTTP/2") {
+			fmt.Fprintf(errW, "http: server did not negotiate HTTP/2 via ALPN, fell back to %s\n", resp.Proto)
+		}
+
+		if (fail || failWithBody) && resp.StatusCode >= 400 {
+			if failWithBody {
+				printRequestAndResponse(out, req, resp, parts, headerCase, colorEnabled, themeName, timing, trimResponse, trimLines, sortHeaders)
+			}
+			return fmt.Errorf("http: request %q failed with status %s", requestLabel(req), resp.Status)
+		}
+
+		if expectations := append(append([]models.Expectation{}, req.Expectations...), globalExpectations...); len(expectations) > 0 {
+			if !printExpectations(out, expectations, resp) {
+				return fmt.Errorf("http: request %q failed one or more --expect assertions", requestLabel(req))
+			}
+		}
+
+		if jar != nil {
+			stateMu.Lock()
+			jar.Merge(req.URL, resp.Cookies)
+			err := jar.Save(cookieJarPath)
+			stateMu.Unlock()
+			if err != nil {
+				return fatalRequestError{err}
+			}
+		}
+
+		if outputPath != "" {
+			if err := writeOutput(outputPath, resp, resume); err != nil {
+				return err
+			}
+		} else if extractPath != "" {
+			value, err := chain.ExtractJSONPath(resp.Body, strings.TrimPrefix(extractPath, "$."))
+			if err != nil {
+				return fmt.Errorf("http: --extract %q: %w", extractPath, err)
+			}
+			fmt.Fprintln(out, value)
+		} else if !stream {
+			printRequestAndResponse(out, req, resp, parts, headerCase, colorEnabled, themeName, timing, trimResponse, trimLines, sortHeaders)
+			if discover {
+				printDiscovery(out, resp.Header)
+			}
+			if effectiveURL {
+				fmt.Fprintf(out, "effective url: %s\n", resp.FinalURL)
+			}
+		} else if stream && timing {
+			fmt.Fprintf(out, "time: %s\n", resp.Timing)
+		}
+
+		if summary != "" {
+			w := out
+			if summary == "stderr" {
+				w = stderr
+			}
+			writeSummary(w, req, resp)
+		}
+
+		if maxResponseTimeDuration > 0 && resp.Timing > maxResponseTimeDuration {
+			return fmt.Errorf("http: request %q took %s, exceeding --max-response-time %s", requestLabel(req), resp.Timing, maxResponseTimeDuration)
+		}
+
+		if hashAlgo != "" {
+			sum, err := hashBody(hashAlgo, resp.RawBody)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(out, "%s: %s\n", hashAlgo, sum)
+			if expectHash != "" && !strings.EqualFold(sum, expectHash) {
+				return fmt.Errorf("http: response body hash %s does not match --expect-hash %s", sum, expectHash)
+			}
+		}
+
+		if saveBaselinePath != "" {
+			if err := saveBaseline(saveBaselinePath, resp); err != nil {
+				return err
+			}
+		}
+		if baselinePath != "" {
+			baseline, err := loadBaseline(baselinePath)
+			if err != nil {
+				return err
+			}
+			diff := diffBaseline(baseline, resp, baselineIgnoreHeaders)
+			if diff != "" {
+				fmt.Fprint(out, diff)
+				return fmt.Errorf("http: response differs from baseline %q", baselinePath)
+			}
+		}
+
+		stateMu.Lock()
+		err = store.Capture(resp, req.Captures)
+		stateMu.Unlock()
+		if err != nil {
+			return err
+		}
+		return nil
+	}
+
+	// handleResult applies a sendOne outcome the same way regardless of
+	// whether it ran sequentially or as one of --parallel's workers:
+	// a fatalRequestError always aborts, otherwise --continue-on-error
+	// decides whether the batch keeps going.
+	handleResult := func(req *models.Request, err error) (abort bool) {
+		if err == nil {
+			return false
+		}
+		var fatal fatalRequestError
+		if errors.As(err, &fatal) {
+			fmt.Fprintln(stderr, fatal.error)
+			return true
+		}
+		recordFailure(req, err)
+		return !continueOnError
+	}
+
+	if parallel > 1 {
+		type result struct {
+			out, errW bytes.Buffer
+			err       error
+		}
+		results := make([]result, len(requestsToRun))
+		sem := make(chan struct{}, parallel)
+		var wg sync.WaitGroup
+		for i, req := range requestsToRun {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, req *models.Request) {
+				defer wg.Done()
+				defer func() { <-sem }()
+				results[i].err = sendOne(req, &results[i].out, &results[i].errW)
+			}(i, req)
+		}
+		wg.Wait()
+
+		aborted := false
+		completed := 0
+		for i, req := range requestsToRun {
+			io.Copy(stdout, &results[i].out)
+			io.Copy(stderr, &results[i].errW)
+			if results[i].err == nil {
+				completed++
+			}
+			if handleResult(req, results[i].err) {
+				aborted = true
+			}
+		}
+		if aborted {
+			if interruptCtx.Err() != nil {
+				return reportInterrupted(stderr, completed, len(requestsToRun))
+			}
+			return 1
+		}
+	} else {
+		completed := 0
+		for _, req := range requestsToRun {
+			err := sendOne(req, stdout, stderr)
+			if err == nil {
+				completed++
+			}
+			if handleResult(req, err) {
+				if interruptCtx.Err() != nil {
+					return reportInterrupted(stderr, completed, len(requestsToRun))
+				}
+				return 1
+			}
+		}
+	}
+
+	if continueOnError && len(failures) > 0 {
+		fmt.Fprintf(stderr, "http: %d of %d requests failed:\n", len(failures), len(requestsToRun))
+		for _, f := range failures {
+			fmt.Fprintf(stderr, "  %s\n", f)
+		}
+		return 1
+	}
+
+	if trace != nil {
+		f, err := os.Create(harPath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		defer f.Close()
+		if err := trace.Write(f); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	if recordedExchange != nil {
+		f, err := os.Create(recordPath)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		defer f.Close()
+		if err := recordedExchange.Write(f); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	return 0
+}
+
+// prepareRequest resolves any "{{var}}" placeholders in req against
+// store, then applies query parameters, scheme and method overrides,
+// form-body construction, --no-body stripping, and cookie jar
+// attachment, and validates the result.
+func prepareRequest(req *models.Request, store chain.Store, query keyValueList, forceHTTP, forceHTTPS, head, discover, noBody bool, jar *cookiejar.Jar, cache *reqcache.Cache, form formInput, vopts validate.ValidateOptions, compress, userAgent, requestTarget string, noDefaultHeaders, prompt bool, stdin io.Reader, stderr io.Writer) (warnings []string, err error) {
+	resolveErr := resolveRequest(req, store)
+	if prompt && resolveErr != nil {
+		var undefined *chain.UndefinedVariableError
+		if errors.As(resolveErr, &undefined) {
+			resolveErr = promptForUndefinedVariables(req, store, stdin, stderr)
+		}
+	}
+	if resolveErr != nil {
+		return nil, fmt.Errorf("request %q: %w", requestLabel(req), resolveErr)
+	}
+
+	applyUserAgent(req, userAgent, noDefaultHeaders)
+
+	query.applyTo(req.URL)
+
+	switch {
+	case forceHTTP:
+		req.URL.Scheme = "http"
+	case forceHTTPS:
+		req.URL.Scheme = "https"
+	}
+
+	switch {
+	case head:
+		req.Method = http.MethodHead
+	case discover:
+		req.Method = http.MethodOptions
+	}
+
+	switch requestTarget {
+	case "authority":
+		if !req.IsConnect() {
+			return nil, fmt.Errorf("request %q: --request-target authority requires a CONNECT request", requestLabel(req))
+		}
+	case "asterisk":
+		if req.Method != http.MethodOptions {
+			return nil, fmt.Errorf("request %q: --request-target asterisk requires an OPTIONS request", requestLabel(req))
+		}
+		if req.Header.Get("Host") == "" && req.URL.Host != "" {
+			// Asterisk-form carries no authority of its own, so forcing it
+			// onto a request that named its host in the URL (rather than an
+			// explicit Host header, as OPTIONS * naturally requires) needs
+			// that host preserved here before it's discarded below.
+			req.SetHeader("Host", req.URL.Host)
+		}
+		req.URL.Path = "*"
+		req.URL.RawPath = ""
+		req.URL.RawQuery = ""
+	}
+
+	if !form.empty() {
+		if err := form.apply(req); err != nil {
+			return nil, fmt.Errorf("request %q: %w", requestLabel(req), err)
+		}
+	}
+
+	if noBody {
+		req.Body = ""
+		req.DeleteHeader("Content-Length")
+		req.DeleteHeader("Content-Type")
+	} else if err := compressBody(req, compress); err != nil {
+		return nil, fmt.Errorf("request %q: %w", requestLabel(req), err)
+	}
+
+	if jar != nil {
+		attachCookies(req, jar)
+	}
+
+	if cache != nil && req.Method == http.MethodGet {
+		applyCacheValidators(req, cache)
+	}
+
+	return validate.Validate(req, vopts)
+}
+
+// applyCacheValidators adds If-None-Match/If-Modified-Since to req from
+// whatever --cache-dir has on file for its URL, so an unchanged
+// response comes back as a cheap 304 instead of a full body. It never
+// overwrites a validator the .http file already set explicitly.
+func applyCacheValidators(req *models.Request, cache *reqcache.Cache) {
+	entry := cache.Get(req.URL)
+	if entry == nil {
+		return
+	}
+	if entry.ETag != "" && req.Header.Get("If-None-Match") == "" {
+		req.SetHeader("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" && req.Header.Get("If-Modified-Since") == "" {
+		req.SetHeader("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// updateCache reconciles --cache-dir's on-disk entry for req.URL with
+// resp: a 304 has its body filled in from the cached copy (or, lacking
+// one, a note is printed instead of leaving it empty), while any other
+// response carrying an ETag or Last-Modified is cached for next time.
+func updateCache(cache *reqcache.Cache, req *models.Request, resp *models.HTTPResponse, stderr io.Writer) {
+	if resp.StatusCode == http.StatusNotModified {
+		entry := cache.Get(req.URL)
+		if entry == nil {
+			fmt.Fprintf(stderr, "http: %s: 304 Not Modified but --cache-dir has no cached body for it\n", requestLabel(req))
+			return
+		}
+		resp.Body = entry.Body
+		resp.RawBody = []byte(entry.Body)
+		return
+	}
+
+	etag := resp.Header.Get("ETag")
+	lastModified := resp.Header.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+	entry := &reqcache.Entry{ETag: etag, LastModified: lastModified, StatusCode: resp.StatusCode, Body: resp.Body}
+	if err := cache.Put(req.URL, entry); err != nil {
+		fmt.Fprintf(stderr, "http: --cache-dir: %v\n", err)
+	}
+}
+
+// resolveRequest substitutes captured values from store into req's
+// target, headers, and body, re-parsing the URL from the resolved
+// target.
+func resolveRequest(req *models.Request, store chain.Store) error {
+	target, err := store.Resolve(req.RawTarget)
+	if err != nil {
+		return fmt.Errorf("target: %w", err)
+	}
+	u, err := httpfile.ParseTarget(req.Method, target)
+	if err != nil {
+		return fmt.Errorf("invalid request target %q: %w", target, err)
+	}
+	req.URL = u
+
+	resolvedByCanonical := make(map[string]string, len(req.Header))
+	for name, values := range req.Header {
+		for i, v := range values {
+			resolved, err := store.Resolve(v)
+			if err != nil {
+				return fmt.Errorf("header %s: %w", name, err)
+			}
+			values[i] = resolved
+		}
+		if len(values) > 0 {
+			resolvedByCanonical[name] = values[0]
+		}
+	}
+	// RawHeaders holds the same values in their original casing, for
+	// --header-case; keep it in step with the substitution above. This
+	// only tracks one value per header name, matching the common case
+	// of a header appearing once in a .http file.
+	for i, h := range req.RawHeaders {
+		if resolved, ok := resolvedByCanonical[http.CanonicalHeaderKey(h.Name)]; ok {
+			req.RawHeaders[i].Value = resolved
+		}
+	}
+
+	body, err := store.Resolve(req.Body)
+	if err != nil {
+		return fmt.Errorf("body: %w", err)
+	}
+	req.Body = body
+	return nil
+}
+
+// expandFileArgs turns the command-line file arguments into a flat list
+// of paths in the order requests should be sent: command-line order,
+// then, for an argument that's a glob pattern, the glob's own
+// (alphabetical) match order. An argument that isn't a glob pattern, or
+// that doesn't match anything, is kept as-is so a missing file still
+// produces the usual "no such file" error from os.Open.
+func expandFileArgs(args []string) ([]string, error) {
+	var paths []string
+	for _, arg := range args {
+		if !strings.ContainsAny(arg, "*?[") {
+			paths = append(paths, arg)
+			continue
+		}
+		matches, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("http: invalid glob %q: %w", arg, err)
+		}
+		if len(matches) == 0 {
+			paths = append(paths, arg)
+			continue
+		}
+		paths = append(paths, matches...)
+	}
+	return paths, nil
+}
+
+// parseFile opens and parses path, tagging every request it contains
+// with its source file so multi-file runs can print which file each
+// request came from. maxHeaderLines is forwarded to httpfile.Parse; 0
+// uses its default. format selects the parser: "http" always uses the
+// .http text format, "json" always uses reqjson, and "auto" picks json
+// for a ".json" extension and http otherwise.
+func parseFile(path string, maxHeaderLines int, format string) ([]*models.Request, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	if usesJSONFormat(path, format) {
+		req, err := reqjson.Parse(f)
+		if err != nil {
+			return nil, nil, err
+		}
+		req.SourceFile = path
+		return []*models.Request{req}, nil, nil
+	}
+
+	requests, warnings, err := httpfile.Parse(f, maxHeaderLines)
+	if err != nil {
+		return nil, warnings, err
+	}
+	for _, r := range requests {
+		r.SourceFile = path
+	}
+	return requests, warnings, nil
+}
+
+// usesJSONFormat reports whether path should be parsed with reqjson
+// rather than httpfile, given the --format flag's value.
+func usesJSONFormat(path, format string) bool {
+	switch format {
+	case "json":
+		return true
+	case "http":
+		return false
+	default:
+		return strings.EqualFold(filepath.Ext(path), ".json")
+	}
+}
+
+// fileExists reports whether path exists and is readable, for locating
+// the default --config path without treating "not there" as an error.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// attachCookies sets the Cookie header on req from every jar entry
+// matching req.URL, merging with any Cookie header already present.
+func attachCookies(req *models.Request, jar *cookiejar.Jar) {
+	cookies := jar.For(req.URL)
+	if len(cookies) == 0 {
+		return
+	}
+	var pairs []string
+	if existing := req.Header.Get("Cookie"); existing != "" {
+		pairs = append(pairs, existing)
+	}
+	for _, c := range cookies {
+		pairs = append(pairs, c.Name+"="+c.Value)
+	}
+	req.SetHeader("Cookie", strings.Join(pairs, "; "))
+}
@@ -0,0 +1,115 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeJSONRequestFile(t *testing.T, srvURL string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "req.http")
+	contents := "GET " + srvURL + "/\n\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun_ColorAutoOnNonTerminalStdoutPrintsPlainBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	path := writeJSONRequestFile(t, srv.URL)
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "\x1b[") {
+		t.Errorf("expected no ANSI codes with default --color auto on a non-terminal stdout, got %q", stdout.String())
+	}
+}
+
+func TestRun_ColorAlwaysColorsBodyEvenOnNonTerminalStdout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	path := writeJSONRequestFile(t, srv.URL)
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--color", "always", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "\x1b[") {
+		t.Errorf("expected ANSI codes with --color always, got %q", stdout.String())
+	}
+}
+
+func TestRun_NoColorEnvOverridesColorAlways(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	path := writeJSONRequestFile(t, srv.URL)
+	t.Setenv("NO_COLOR", "1")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--color", "always", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if strings.Contains(stdout.String(), "\x1b[") {
+		t.Errorf("expected NO_COLOR to suppress ANSI codes even with --color always, got %q", stdout.String())
+	}
+}
+
+func TestRun_InvalidColorErrors(t *testing.T) {
+	path := writeJSONRequestFile(t, "https://example.com")
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--color", "sometimes", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for an invalid --color value")
+	}
+}
+
+func TestRun_InvalidThemeErrors(t *testing.T) {
+	path := writeJSONRequestFile(t, "https://example.com")
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--theme", "neon", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for an invalid --theme value")
+	}
+}
+
+func TestRun_ThemeLightUsesADifferentPaletteThanDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	path := writeJSONRequestFile(t, srv.URL)
+	var defaultOut, lightOut, stderr bytes.Buffer
+	if code := Run([]string{"--color", "always", path}, nil, &defaultOut, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if code := Run([]string{"--color", "always", "--theme", "light", path}, nil, &lightOut, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if defaultOut.String() == lightOut.String() {
+		t.Error("expected --theme light to produce different ANSI codes than the default theme")
+	}
+}
@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// compressBody gzip-compresses req.Body in place when scheme is "gzip",
+// setting Content-Encoding and clearing any stale Content-Length so
+// prepareRequest's later auto-header step recomputes it for the
+// compressed size. An empty scheme is a no-op, so --compress defaults
+// to sending the body unchanged.
+func compressBody(req *models.Request, scheme string) error {
+	switch scheme {
+	case "":
+		return nil
+	case "gzip":
+	default:
+		return fmt.Errorf("unsupported --compress scheme %q, expected gzip", scheme)
+	}
+	if req.Body == "" {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(req.Body)); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	req.Body = buf.String()
+	req.SetHeader("Content-Encoding", "gzip")
+	req.DeleteHeader("Content-Length")
+	return nil
+}
@@ -0,0 +1,98 @@
+package cli
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_CompressGzipsRequestBody(t *testing.T) {
+	const original = "the quick brown fox jumps over the lazy dog"
+
+	var gotEncoding, gotLength string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotEncoding = r.Header.Get("Content-Encoding")
+		gotLength = r.Header.Get("Content-Length")
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotBody = body
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	content := "POST " + srv.URL + "/\n\n" + original
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--compress", "gzip", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	if gotEncoding != "gzip" {
+		t.Errorf("expected Content-Encoding: gzip, got %q", gotEncoding)
+	}
+	if gotLength != fmt.Sprint(len(gotBody)) {
+		t.Errorf("expected Content-Length %d to match the compressed body, got %q", len(gotBody), gotLength)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(gotBody))
+	if err != nil {
+		t.Fatalf("expected a gzip-decodable body: %v", err)
+	}
+	decoded, err := io.ReadAll(gz)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(decoded) != original {
+		t.Errorf("expected the decompressed body to match the original, got %q", decoded)
+	}
+}
+
+func TestRun_CompressUnsupportedSchemeErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("POST http://example.invalid/\n\nhello"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--compress", "br", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for an unsupported --compress scheme")
+	}
+}
+
+func TestRun_NoSendWithCompressShowsBinaryPlaceholderAndCompressedLength(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("POST http://example.invalid/\n\nhello world"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--no-send", "--compress", "gzip", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	got := stdout.String()
+	if !bytes.Contains([]byte(got), []byte("Content-Encoding: gzip")) {
+		t.Errorf("expected Content-Encoding header in output, got %q", got)
+	}
+	if !bytes.Contains([]byte(got), []byte("[binary data,")) {
+		t.Errorf("expected a binary data placeholder, got %q", got)
+	}
+	if bytes.Contains([]byte(got), []byte("hello world")) {
+		t.Errorf("expected the raw uncompressed body not to be printed, got %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_ConfigSuppliesDefaultProxyForMatchingHost(t *testing.T) {
+	dir := t.TempDir()
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET http://example.com/path\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := filepath.Join(dir, "config.toml")
+	cfg := `
+[hosts."example.com"]
+proxy = "http://from-config:8080"
+`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--config", cfgPath, "--no-send", reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "GET http://example.com/path ") {
+		t.Errorf("expected the config proxy to trigger absolute-form output, got %q", stdout.String())
+	}
+}
+
+func TestRun_ConfigDoesNotApplyToUnmatchedHost(t *testing.T) {
+	dir := t.TempDir()
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET http://other.example.com/path\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := filepath.Join(dir, "config.toml")
+	cfg := `
+[hosts."example.com"]
+proxy = "http://from-config:8080"
+`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--config", cfgPath, "--no-send", reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "GET /path ") {
+		t.Errorf("expected origin-form output for a host not covered by the config, got %q", stdout.String())
+	}
+}
+
+func TestRun_ExplicitFlagOverridesConfig(t *testing.T) {
+	dir := t.TempDir()
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET http://example.com/path\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := filepath.Join(dir, "config.toml")
+	cfg := `
+[hosts."example.com"]
+proxy = "http://from-config:8080"
+`
+	if err := os.WriteFile(cfgPath, []byte(cfg), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--config", cfgPath, "--proxy", "", "--no-send", reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "GET /path ") {
+		t.Errorf("expected an explicit empty --proxy to win over the config proxy, got %q", stdout.String())
+	}
+}
+
+func TestRun_ConfigDefaultTimeoutIsInvalidDurationFails(t *testing.T) {
+	dir := t.TempDir()
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET http://example.com/path\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	cfgPath := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(cfgPath, []byte("timeout = \"soon\"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--config", cfgPath, "--no-send", reqPath}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a malformed config file to be reported as an error")
+	}
+}
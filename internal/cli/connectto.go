@@ -0,0 +1,72 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// connectToList collects repeated "host:port:connecthost:connectport"
+// --connect-to flag occurrences and turns them into the dial-address
+// overrides client.Options.ConnectTo expects.
+type connectToList []string
+
+func (l *connectToList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *connectToList) Set(s string) error {
+	if _, _, err := parseConnectToEntry(s); err != nil {
+		return err
+	}
+	*l = append(*l, s)
+	return nil
+}
+
+// toMap turns every collected "host:port:connecthost:connectport" entry
+// into a map from the dial address net/http actually asks for
+// ("host:port") to the address to dial instead
+// ("connecthost:connectport"), so the Host header and TLS SNI/certificate
+// verification—both derived from the original host:port—are left
+// untouched.
+func (l connectToList) toMap() (map[string]string, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]string, len(l))
+	for _, s := range l {
+		hostPort, connectAddr, err := parseConnectToEntry(s)
+		if err != nil {
+			return nil, err
+		}
+		m[hostPort] = connectAddr
+	}
+	return m, nil
+}
+
+// parseConnectToEntry splits a "host:port:connecthost:connectport"
+// --connect-to value into the original dial address and its override.
+// host and connecthost may each be a bracketed IPv6 literal ("[::1]",
+// "[fe80::1%eth0]"), since without brackets there'd be no way to tell
+// their colons apart from the host:port:connecthost:connectport
+// separators.
+func parseConnectToEntry(s string) (hostPort, connectAddr string, err error) {
+	malformed := fmt.Errorf("invalid --connect-to value %q, expected host:port:connecthost:connectport", s)
+
+	host, rest, splitErr := splitBracketedHost(s)
+	if splitErr != nil {
+		return "", "", malformed
+	}
+	port, rest, ok := strings.Cut(rest, ":")
+	if !ok || port == "" {
+		return "", "", malformed
+	}
+	connectHost, connectPort, splitErr := splitBracketedHost(rest)
+	if splitErr != nil || connectHost == "" || connectPort == "" || strings.Contains(connectPort, ":") {
+		return "", "", malformed
+	}
+	return net.JoinHostPort(host, port), net.JoinHostPort(connectHost, connectPort), nil
+}
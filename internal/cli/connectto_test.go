@@ -0,0 +1,47 @@
+package cli
+
+import "testing"
+
+func TestConnectToList_ToMap(t *testing.T) {
+	var l connectToList
+	if err := l.Set("api.example.com:443:backend-7.internal:8443"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := l.toMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m["api.example.com:443"]; got != "backend-7.internal:8443" {
+		t.Errorf("expected the connect-to target, got %q", got)
+	}
+}
+
+func TestConnectToList_SetRejectsMalformedEntry(t *testing.T) {
+	var l connectToList
+	if err := l.Set("api.example.com:443:backend-7.internal"); err == nil {
+		t.Fatal("expected an error for a malformed --connect-to value")
+	}
+}
+
+func TestConnectToList_ToMap_IPv6Literals(t *testing.T) {
+	var l connectToList
+	if err := l.Set("[::1]:443:[fe80::1%eth0]:8443"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := l.toMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m["[::1]:443"]; got != "[fe80::1%eth0]:8443" {
+		t.Errorf("expected the bracketed IPv6 connect-to target, got %q", got)
+	}
+}
+
+func TestConnectToList_SetRejectsUnterminatedBracket(t *testing.T) {
+	var l connectToList
+	if err := l.Set("[::1:443:backend.internal:8443"); err == nil {
+		t.Fatal("expected an error for an unterminated \"[\"")
+	}
+}
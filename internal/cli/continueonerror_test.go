@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// refusedPortTarget returns a URL nothing is listening on, so connecting
+// to it fails immediately with "connection refused".
+func refusedPortTarget(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return "http://" + addr + "/"
+}
+
+func TestRun_ContinueOnErrorRunsAllRequestsAndSummarizesFailures(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "ok:%s", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "batch.http")
+	contents := "### first\nGET " + srv.URL + "/first\n\n" +
+		"### middle\nGET " + refusedPortTarget(t) + "\n\n" +
+		"### last\nGET " + srv.URL + "/last\n\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--continue-on-error", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit since one of the three requests failed")
+	}
+	if !strings.Contains(stdout.String(), "ok:/first") {
+		t.Errorf("expected the first request's response in stdout, got %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "ok:/last") {
+		t.Errorf("expected the last request to still run despite the middle one failing, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "middle") {
+		t.Errorf("expected the failure summary to name the failed request, got %q", stderr.String())
+	}
+}
+
+func TestRun_WithoutContinueOnErrorAbortsAtFirstFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "ok:%s", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "batch.http")
+	contents := "### first\nGET " + srv.URL + "/first\n\n" +
+		"### middle\nGET " + refusedPortTarget(t) + "\n\n" +
+		"### last\nGET " + srv.URL + "/last\n\n"
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit")
+	}
+	if strings.Contains(stdout.String(), "ok:/last") {
+		t.Errorf("expected the run to abort before the last request without --continue-on-error, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,96 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRun_DeclarationsResolveInTheURLAndAHeader asserts a file-level
+// "@name = value" declaration substitutes into both the request line and
+// a header, the way an --environment profile variable already does.
+func TestRun_DeclarationsResolveInTheURLAndAHeader(t *testing.T) {
+	var gotPath, gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotHeader = r.Header.Get("X-Api-Key")
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	body := fmt.Sprintf(`@base = %s
+@key = secret123
+GET {{base}}/widgets
+X-Api-Key: {{key}}
+
+`, srv.URL)
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-only", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotPath != "/widgets" {
+		t.Errorf("expected the declared @base to resolve in the URL, got path %q", gotPath)
+	}
+	if gotHeader != "secret123" {
+		t.Errorf("expected the declared @key to resolve in the header, got %q", gotHeader)
+	}
+}
+
+// TestRun_LaterDeclarationOverridesAnEarlierOne asserts redeclaring the
+// same name later in the file wins, matching how a later "###" block's
+// captures already shadow an earlier one's.
+func TestRun_LaterDeclarationOverridesAnEarlierOne(t *testing.T) {
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Name")
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	body := fmt.Sprintf(`@name = first
+@name = second
+GET %s/
+X-Name: {{name}}
+
+`, srv.URL)
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-only", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotHeader != "second" {
+		t.Errorf("expected the later declaration to win, got %q", gotHeader)
+	}
+}
+
+// TestRun_ValidateOnlySeesDeclaredVariables asserts declarations are
+// available even on the single-request paths that don't run sendOne's
+// full pipeline.
+func TestRun_ValidateOnlySeesDeclaredVariables(t *testing.T) {
+	body := "@path = widgets\nGET https://example.com/{{path}}\n\n"
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--validate-only", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stdout=%s stderr=%s", code, stdout.String(), stderr.String())
+	}
+}
@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseDigestUser splits a "--user user:pass" value into its username
+// and password parts for --digest.
+func parseDigestUser(spec string) (user, password string, err error) {
+	user, password, ok := strings.Cut(spec, ":")
+	if !ok {
+		return "", "", fmt.Errorf("--user must be in user:pass form")
+	}
+	if user == "" {
+		return "", "", fmt.Errorf("--user must include a username")
+	}
+	return user, password, nil
+}
@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// printDiscovery writes a friendly, parsed summary of header's Allow and
+// Access-Control-Allow-* values after the normal response output, for
+// --discover. Each header's comma-separated value is split and
+// re-joined with consistent spacing; a header that's absent from the
+// response is skipped.
+func printDiscovery(w io.Writer, header http.Header) {
+	if allow, ok := splitCommaList(header.Get("Allow")); ok {
+		fmt.Fprintf(w, "allowed methods: %s\n", strings.Join(allow, ", "))
+	}
+	for _, name := range sortedHeaderNamesWithPrefix(header, "Access-Control-Allow-") {
+		values, ok := splitCommaList(header.Get(name))
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "%s: %s\n", name, strings.Join(values, ", "))
+	}
+}
+
+// splitCommaList splits a comma-separated header value into its
+// trimmed parts, reporting false for an empty value.
+func splitCommaList(value string) (parts []string, ok bool) {
+	if value == "" {
+		return nil, false
+	}
+	for _, part := range strings.Split(value, ",") {
+		parts = append(parts, strings.TrimSpace(part))
+	}
+	return parts, true
+}
+
+// sortedHeaderNamesWithPrefix returns header's canonical names that
+// start with prefix, sorted, so --discover's CORS output is
+// deterministic regardless of wire order.
+func sortedHeaderNamesWithPrefix(header http.Header, prefix string) []string {
+	var names []string
+	for name := range header {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_DiscoverForcesOptionsAndPrintsAllowedMethods(t *testing.T) {
+	var gotMethod string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		w.Header().Set("Allow", "GET, POST, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Methods", "GET,POST")
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--discover", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	if gotMethod != http.MethodOptions {
+		t.Errorf("expected the server to see an OPTIONS request, got %q", gotMethod)
+	}
+
+	out := stdout.String()
+	if !bytes.Contains([]byte(out), []byte("allowed methods: GET, POST, OPTIONS\n")) {
+		t.Errorf("expected a friendly allowed-methods line, got %q", out)
+	}
+	if !bytes.Contains([]byte(out), []byte("Access-Control-Allow-Methods: GET, POST\n")) {
+		t.Errorf("expected a friendly CORS methods line, got %q", out)
+	}
+	if !bytes.Contains([]byte(out), []byte("Access-Control-Allow-Origin: *\n")) {
+		t.Errorf("expected a friendly CORS origin line, got %q", out)
+	}
+	if !bytes.Contains([]byte(out), []byte("Allow: GET, POST, OPTIONS")) {
+		t.Errorf("expected the raw Allow header to still print, got %q", out)
+	}
+}
+
+func TestRun_DiscoverAndHeadAreMutuallyExclusive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--discover", "--head", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --discover combined with --head")
+	}
+}
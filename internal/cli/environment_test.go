@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_EnvironmentFlagResolvesVariablesPerProfile(t *testing.T) {
+	var gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Header.Get("X-Target-Host")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	envJSON := `{
+		"dev": {"host": "dev.example.com"},
+		"prod": {"host": "prod.example.com"}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, "http-client.env.json"), []byte(envJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\nX-Target-Host: {{host}}\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--environment", "dev", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotHost != "dev.example.com" {
+		t.Errorf("dev environment: got host %q, want %q", gotHost, "dev.example.com")
+	}
+
+	code = Run([]string{"--environment", "prod", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotHost != "prod.example.com" {
+		t.Errorf("prod environment: got host %q, want %q", gotHost, "prod.example.com")
+	}
+}
+
+func TestRun_EnvironmentUnknownProfileErrors(t *testing.T) {
+	dir := t.TempDir()
+	envJSON := `{"dev": {"host": "dev.example.com"}}`
+	if err := os.WriteFile(filepath.Join(dir, "http-client.env.json"), []byte(envJSON), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET https://{{host}}/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--environment", "staging", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for an environment name not present in the file")
+	}
+}
+
+func TestRun_EnvironmentMissingFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET https://{{host}}/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--environment", "dev", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit when http-client.env.json doesn't exist")
+	}
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// parseExpectHeaderFlag parses a repeated --expect-header value of the
+// form "Name: value" into a models.Expectation, matching the directive
+// grammar in internal/httpfile.
+func parseExpectHeaderFlag(raw string) (models.Expectation, error) {
+	name, value, ok := strings.Cut(raw, ":")
+	if !ok {
+		return models.Expectation{}, fmt.Errorf("http: invalid --expect-header %q, expected Name: value", raw)
+	}
+	return models.Expectation{Kind: models.ExpectHeader, Header: strings.TrimSpace(name), Value: strings.TrimSpace(value)}, nil
+}
+
+// parseExpectStatusFlag parses a repeated --expect-status value into a
+// models.Expectation.
+func parseExpectStatusFlag(raw string) (models.Expectation, error) {
+	code, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil {
+		return models.Expectation{}, fmt.Errorf("http: invalid --expect-status %q: %w", raw, err)
+	}
+	return models.Expectation{Kind: models.ExpectStatus, Status: code}, nil
+}
+
+// evaluateExpectation reports whether exp holds against resp, along
+// with a human-readable description of the check for --expect-*'s
+// pass/fail output.
+func evaluateExpectation(exp models.Expectation, resp *models.HTTPResponse) (pass bool, description string) {
+	switch exp.Kind {
+	case models.ExpectStatus:
+		description = fmt.Sprintf("status %d", exp.Status)
+		return resp.StatusCode == exp.Status, description
+	case models.ExpectHeader:
+		description = fmt.Sprintf("header %s: %s", exp.Header, exp.Value)
+		return matchExpectValue(exp.Value, resp.Header.Get(exp.Header)), description
+	case models.ExpectBodyContains:
+		description = fmt.Sprintf("body contains %q", exp.Value)
+		return matchExpectValue(exp.Value, resp.Body), description
+	default:
+		return false, fmt.Sprintf("unrecognized expectation kind %q", exp.Kind)
+	}
+}
+
+// matchExpectValue reports whether actual satisfies want: as a regular
+// expression when want compiles as one, and as a plain substring
+// otherwise, so a simple "application/json" reads naturally while a
+// pattern like "^application/" also works.
+func matchExpectValue(want, actual string) bool {
+	if re, err := regexp.Compile(want); err == nil {
+		return re.MatchString(actual)
+	}
+	return strings.Contains(actual, want)
+}
+
+// printExpectations evaluates every expectation in exps against resp,
+// printing a "PASS"/"FAIL" line per assertion to w, and reports whether
+// all of them passed.
+func printExpectations(w io.Writer, exps []models.Expectation, resp *models.HTTPResponse) (allPassed bool) {
+	allPassed = true
+	for _, exp := range exps {
+		pass, description := evaluateExpectation(exp, resp)
+		status := "PASS"
+		if !pass {
+			status = "FAIL"
+			allPassed = false
+		}
+		fmt.Fprintf(w, "[%s] expect %s\n", status, description)
+	}
+	return allPassed
+}
@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_ExpectFlagsPassPrintsPassAndExitsZero(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":"1"}`))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{
+		"--expect-status", "201",
+		"--expect-header", "Content-Type: application/json",
+		"--expect-body-contains", "id",
+		path,
+	}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	out := stdout.String()
+	for _, want := range []string{"[PASS] expect status 201", "[PASS] expect header Content-Type: application/json", `[PASS] expect body contains "id"`} {
+		if !bytes.Contains([]byte(out), []byte(want)) {
+			t.Errorf("expected output to contain %q, got %q", want, out)
+		}
+	}
+}
+
+func TestRun_ExpectFlagsFailurePrintsFailAndExitsNonZero(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--expect-status", "201", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for a failed --expect-status assertion")
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("[FAIL] expect status 201")) {
+		t.Errorf("expected a FAIL line, got %q", stdout.String())
+	}
+}
+
+func TestRun_ExpectDirectivesFromFileAreEvaluated(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "post.http")
+	body := "POST " + srv.URL + "\n# @expect status 201\n\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("[PASS] expect status 201")) {
+		t.Errorf("expected a PASS line from the file's @expect directive, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_ExtractPrintsOnlyTheResolvedValue(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"items":[{"id":"a"},{"id":"b"}]}}`))
+	}))
+	defer srv.Close()
+
+	path := writeJSONRequestFile(t, srv.URL)
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--extract", "$.data.items[1].id", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "b" {
+		t.Errorf("stdout = %q, want %q", got, "b")
+	}
+}
+
+func TestRun_ExtractMissingPathErrorsNonZero(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer srv.Close()
+
+	path := writeJSONRequestFile(t, srv.URL)
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--extract", "$.data.token", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit when --extract's path doesn't resolve")
+	}
+	if stderr.String() == "" {
+		t.Error("expected an error message on stderr")
+	}
+}
+
+func TestRun_ExtractNestedObjectField(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"user":{"name":"ada"}}`))
+	}))
+	defer srv.Close()
+
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--extract", "$.user.name", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if got := strings.TrimSpace(stdout.String()); got != "ada" {
+		t.Errorf("stdout = %q, want %q", got, "ada")
+	}
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_FailEarlyReportsAnUnresolvableHost(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://definitely-invalid-host-name.invalid/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--fail-early", path}, nil, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d, stdout=%s stderr=%s", code, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "could not resolve host") {
+		t.Errorf("expected a \"could not resolve host\" error, got %s", stderr.String())
+	}
+}
+
+func TestRun_InvalidFailEarlyTimeoutIsRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--fail-early-timeout", "notaduration", path}, nil, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d, stderr=%s", code, stderr.String())
+	}
+}
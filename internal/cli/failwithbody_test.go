@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_FailWithBodyPrintsBodyThenExitsNonZero(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		fmt.Fprint(w, `{"error":"validation failed"}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--fail-with-body", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for a 422 response with --fail-with-body")
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte(`{"error":"validation failed"}`)) {
+		t.Errorf("expected --fail-with-body to print the response body, got stdout %q", stdout.String())
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("422")) {
+		t.Errorf("expected --fail-with-body to print the status line, got stdout %q", stdout.String())
+	}
+}
+
+func TestRun_FailAndFailWithBodyAreMutuallyExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--fail", "--fail-with-body", path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2 for conflicting flags, got %d", code)
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("mutually exclusive")) {
+		t.Errorf("expected an error mentioning mutual exclusivity, got %q", stderr.String())
+	}
+}
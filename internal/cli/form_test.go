@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_DataBuildsURLEncodedBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("POST http://example.com/submit\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--data", "name=gopher", "--data", "lang=go", "--no-send", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Content-Type: application/x-www-form-urlencoded") {
+		t.Errorf("expected a form-urlencoded Content-Type, got %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "lang=go&name=gopher") {
+		t.Errorf("expected the urlencoded body, got %q", stdout.String())
+	}
+}
+
+func TestRun_FormBuildsMultipartBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("POST http://example.com/submit\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--form", "name=gopher", "--no-send", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Content-Type: multipart/form-data; boundary=") {
+		t.Errorf("expected a multipart Content-Type, got %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), `Content-Disposition: form-data; name="name"`) {
+		t.Errorf("expected the field's Content-Disposition, got %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "gopher") {
+		t.Errorf("expected the field value in the body, got %q", stdout.String())
+	}
+}
+
+func TestRun_FormFileReadsFileFromDisk(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "upload.txt")
+	if err := os.WriteFile(filePath, []byte("hello from disk"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("POST http://example.com/submit\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--form-file", "avatar=@" + filePath, "--no-send", reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "hello from disk") {
+		t.Errorf("expected the file's contents in the body, got %q", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), `filename="upload.txt"`) {
+		t.Errorf("expected the file's basename, got %q", stdout.String())
+	}
+}
+
+func TestRun_FormAndDataAreMutuallyExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("POST http://example.com/submit\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--form", "a=b", "--data", "c=d", "--no-send", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected --form and --data together to be rejected")
+	}
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_JSONRequestFileAutoDetectedByExtension(t *testing.T) {
+	var gotMethod, gotHeader, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotHeader = r.Header.Get("X-Test")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	doc := map[string]any{
+		"method":  "POST",
+		"url":     srv.URL + "/",
+		"headers": map[string]string{"X-Test": "value"},
+		"body":    "hello",
+	}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "req.json")
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotMethod != "POST" {
+		t.Errorf("expected method POST, got %q", gotMethod)
+	}
+	if gotHeader != "value" {
+		t.Errorf("expected header value %q, got %q", "value", gotHeader)
+	}
+	if gotBody != "hello" {
+		t.Errorf("expected body %q, got %q", "hello", gotBody)
+	}
+}
+
+func TestRun_JSONAndHTTPRequestsSendIdentically(t *testing.T) {
+	var jsonRequest, httpRequest string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		line := r.Method + " " + string(body)
+		if jsonRequest == "" {
+			jsonRequest = line
+		} else {
+			httpRequest = line
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	jsonPath := filepath.Join(dir, "req.json")
+	doc := map[string]any{"method": "PUT", "url": srv.URL + "/", "body": "payload"}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jsonPath, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	httpPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(httpPath, []byte("PUT "+srv.URL+"/\n\npayload"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{jsonPath}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0 for json request, got %d, stderr=%s", code, stderr.String())
+	}
+	stdout.Reset()
+	stderr.Reset()
+	if code := Run([]string{httpPath}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0 for http request, got %d, stderr=%s", code, stderr.String())
+	}
+
+	if jsonRequest != httpRequest {
+		t.Errorf("expected the json and http forms to send identically, got %q and %q", jsonRequest, httpRequest)
+	}
+}
+
+func TestRun_FormatFlagOverridesExtension(t *testing.T) {
+	var gotMethod string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.txt")
+	doc := map[string]any{"method": "DELETE", "url": srv.URL + "/"}
+	raw, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--format", "json", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotMethod != "DELETE" {
+		t.Errorf("expected method DELETE, got %q", gotMethod)
+	}
+}
+
+func TestRun_JSONRequestRejectsUnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.json")
+	if err := os.WriteFile(path, []byte(`{"method":"GET","url":"http://example.invalid/","hedaers":{}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for an unknown field")
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("hedaers")) {
+		t.Errorf("expected the error to name the unknown field, got %q", stderr.String())
+	}
+}
+
+func TestRun_InvalidFormatFlagRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.invalid/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--format", "yaml", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for an unsupported --format value")
+	}
+	if !bytes.Contains(stderr.Bytes(), []byte("--format")) {
+		t.Errorf("expected the error to mention --format, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adamijak/http/internal/formbody"
+	"github.com/adamijak/http/internal/models"
+)
+
+// formFieldList collects repeated "name=value" --form/--data flag
+// occurrences into formbody.Field values.
+type formFieldList []formbody.Field
+
+func (l *formFieldList) String() string {
+	if l == nil {
+		return ""
+	}
+	parts := make([]string, len(*l))
+	for i, f := range *l {
+		parts[i] = f.Name + "=" + f.Value
+	}
+	return strings.Join(parts, ",")
+}
+
+func (l *formFieldList) Set(s string) error {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("invalid value %q, expected name=value", s)
+	}
+	*l = append(*l, formbody.Field{Name: name, Value: value})
+	return nil
+}
+
+// formFileList collects repeated "name=@path" --form-file flag
+// occurrences into formbody.FileField values.
+type formFileList []formbody.FileField
+
+func (l *formFileList) String() string {
+	if l == nil {
+		return ""
+	}
+	parts := make([]string, len(*l))
+	for i, f := range *l {
+		parts[i] = f.Name + "=@" + f.Path
+	}
+	return strings.Join(parts, ",")
+}
+
+func (l *formFileList) Set(s string) error {
+	name, path, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("invalid value %q, expected name=@path", s)
+	}
+	path, ok = strings.CutPrefix(path, "@")
+	if !ok {
+		return fmt.Errorf("invalid value %q, expected name=@path", s)
+	}
+	*l = append(*l, formbody.FileField{Name: name, Path: path})
+	return nil
+}
+
+// formInput bundles the --form/--form-file/--data flag values into one
+// concern for prepareRequest: how (if at all) to build the request
+// body. At most one of (fields or files) and data may be set; Run
+// checks that before requests are sent.
+type formInput struct {
+	fields formFieldList
+	files  formFileList
+	data   formFieldList
+}
+
+func (f formInput) empty() bool {
+	return len(f.fields) == 0 && len(f.files) == 0 && len(f.data) == 0
+}
+
+// apply builds req's body from f, when non-empty, overwriting whatever
+// body and Content-Type the .http file declared.
+func (f formInput) apply(req *models.Request) error {
+	switch {
+	case len(f.fields) > 0 || len(f.files) > 0:
+		body, contentType, err := formbody.Multipart(f.fields, f.files)
+		if err != nil {
+			return err
+		}
+		req.Body = body
+		req.SetHeader("Content-Type", contentType)
+	case len(f.data) > 0:
+		req.Body = formbody.URLEncoded(f.data)
+		req.SetHeader("Content-Type", "application/x-www-form-urlencoded")
+	}
+	return nil
+}
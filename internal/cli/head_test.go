@@ -0,0 +1,58 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_HeadForcesMethodAndPrintsOnlyHeaders(t *testing.T) {
+	var gotMethod string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		w.Header().Set("ETag", `"abc123"`)
+		w.Write([]byte("this body should never be sent to a HEAD request"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--head", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	if gotMethod != http.MethodHead {
+		t.Errorf("expected the server to see a HEAD request, got %q", gotMethod)
+	}
+
+	out := stdout.String()
+	if !bytes.Contains([]byte(out), []byte(`Etag: "abc123"`)) {
+		t.Errorf("expected the ETag header to print, got %q", out)
+	}
+	if bytes.Contains([]byte(out), []byte("this body should never be sent")) {
+		t.Errorf("expected no body in --head output, got %q", out)
+	}
+}
+
+func TestRun_HeadAndBodyOnlyAreMutuallyExclusive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--head", "--body-only", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --head combined with --body-only")
+	}
+}
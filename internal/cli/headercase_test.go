@@ -0,0 +1,99 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_HeaderCaseFlagControlsWireCasing(t *testing.T) {
+	tests := []struct {
+		flagValue string
+		wantLine  string
+	}{
+		{"preserve", "x-REQUEST-id: abc123"},
+		{"canonical", "X-Request-Id: abc123"},
+		{"lower", "x-request-id: abc123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.flagValue, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+
+			done := make(chan []string, 1)
+			go func() {
+				conn, err := ln.Accept()
+				if err != nil {
+					done <- nil
+					return
+				}
+				defer conn.Close()
+
+				reader := bufio.NewReader(conn)
+				var lines []string
+				for {
+					line, err := reader.ReadString('\n')
+					if err != nil {
+						break
+					}
+					trimmed := strings.TrimRight(line, "\r\n")
+					if trimmed == "" {
+						break
+					}
+					lines = append(lines, trimmed)
+				}
+				conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"))
+				done <- lines
+			}()
+
+			dir := t.TempDir()
+			path := filepath.Join(dir, "req.http")
+			contents := "GET http://" + ln.Addr().String() + "/\nx-REQUEST-id: abc123\n\n"
+			if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			var stdout, stderr bytes.Buffer
+			code := Run([]string{"--header-case", tt.flagValue, path}, nil, &stdout, &stderr)
+			if code != 0 {
+				t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+			}
+
+			lines := <-done
+			if !containsLine(lines, tt.wantLine) {
+				t.Errorf("expected header line %q on the wire, got %v", tt.wantLine, lines)
+			}
+		})
+	}
+}
+
+func TestRun_InvalidHeaderCaseErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--header-case", "shout", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for an invalid --header-case value")
+	}
+}
+
+func containsLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,36 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// headerList collects repeated "--header Name: value" flag occurrences
+// for a bare-URL positional argument, which has no .http file to
+// declare headers in.
+type headerList []string
+
+func (l *headerList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *headerList) Set(s string) error {
+	if _, _, ok := strings.Cut(s, ":"); !ok {
+		return fmt.Errorf("invalid --header value %q, expected Name: value", s)
+	}
+	*l = append(*l, s)
+	return nil
+}
+
+// applyTo sets every collected header on req.
+func (l headerList) applyTo(req *models.Request) {
+	for _, h := range l {
+		name, value, _ := strings.Cut(h, ":")
+		req.SetHeader(strings.TrimSpace(name), strings.TrimSpace(value))
+	}
+}
@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// headerFileEntry is a Name/Value pair loaded from a --headers-file.
+type headerFileEntry struct {
+	Name  string
+	Value string
+}
+
+// loadHeadersFile parses a "Key: Value" per line headers file (a shared
+// base-headers file a team keeps alongside its .http files for common
+// auth/tracing headers), in the order they appear. A line starting with
+// "#" or "//" is a comment, matching the .http file convention; blank
+// lines are ignored.
+func loadHeadersFile(path string) ([]headerFileEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("headers file: %w", err)
+	}
+	defer f.Close()
+
+	var headers []headerFileEntry
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, fmt.Errorf("headers file: %s:%d: invalid header %q, expected Name: value", path, lineNum, line)
+		}
+		headers = append(headers, headerFileEntry{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("headers file: %w", err)
+	}
+	return headers, nil
+}
+
+// applyHeaderDefaults sets every header in headers on req that req
+// doesn't already declare, so a shared base file supplies defaults that
+// a request's own headers (from its .http file or -H) still override.
+// Env-var/chain substitution of "{{var}}" placeholders happens later,
+// the same as for any other header, since these are merged in before
+// resolveRequest runs.
+func applyHeaderDefaults(req *models.Request, headers []headerFileEntry) {
+	for _, h := range headers {
+		if _, ok := req.Header[http.CanonicalHeaderKey(h.Name)]; ok {
+			continue
+		}
+		req.SetHeader(h.Name, h.Value)
+	}
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// TestApplyHeaderDefaults_InlineHeaderWinsOverHeadersFile merges a loaded
+// headers file (including a Host entry) into a request that already
+// declares its own Host header, and checks the inline value survives.
+func TestApplyHeaderDefaults_InlineHeaderWinsOverHeadersFile(t *testing.T) {
+	dir := t.TempDir()
+	headersPath := filepath.Join(dir, "base-headers.txt")
+	contents := "# shared headers\nAuthorization: Bearer shared-token\nX-Trace-Id: abc123\nHost: base-headers-should-not-win.example\n"
+	if err := os.WriteFile(headersPath, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	headers, err := loadHeadersFile(headersPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := models.New()
+	req.Method = http.MethodGet
+	req.URL, _ = url.Parse("http://example.com/")
+	req.SetHeader("Host", "inline-host.example")
+
+	applyHeaderDefaults(req, headers)
+
+	if got := req.Header.Get("Host"); got != "inline-host.example" {
+		t.Errorf("expected the inline Host header to survive, got %q", got)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer shared-token" {
+		t.Errorf("expected the headers file's Authorization to be merged in, got %q", got)
+	}
+	if got := req.Header.Get("X-Trace-Id"); got != "abc123" {
+		t.Errorf("expected the headers file's X-Trace-Id to be merged in, got %q", got)
+	}
+}
+
+// TestRun_HeadersFileMergesHeadersIntoRequest exercises --headers-file
+// end to end, checking a shared header reaches the server.
+func TestRun_HeadersFileMergesHeadersIntoRequest(t *testing.T) {
+	var gotAuth, gotTrace string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotTrace = r.Header.Get("X-Trace-Id")
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	headersPath := filepath.Join(dir, "base-headers.txt")
+	contents := "Authorization: Bearer shared-token\nX-Trace-Id: abc123\n"
+	if err := os.WriteFile(headersPath, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte(fmt.Sprintf("GET %s/\n\n", srv.URL)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--headers-file", headersPath, reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotAuth != "Bearer shared-token" {
+		t.Errorf("expected the headers file's Authorization to be merged in, got %q", gotAuth)
+	}
+	if gotTrace != "abc123" {
+		t.Errorf("expected the headers file's X-Trace-Id to be merged in, got %q", gotTrace)
+	}
+}
+
+func TestRun_HeadersFileRejectsMalformedLine(t *testing.T) {
+	dir := t.TempDir()
+	headersPath := filepath.Join(dir, "base-headers.txt")
+	if err := os.WriteFile(headersPath, []byte("not-a-header-line\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--headers-file", headersPath, reqPath}, nil, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d, stderr=%s", code, stderr.String())
+	}
+}
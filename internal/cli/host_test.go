@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestRun_HostOverridesHostHeaderWhileDialingTheURLsHost connects to the
+// loopback server by its literal address but asks --host to report a
+// virtual hostname, mirroring the "dial an IP, control Host
+// independently" virtual-host testing workflow.
+func TestRun_HostOverridesHostHeaderWhileDialingTheURLsHost(t *testing.T) {
+	var gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-only", "--host", "testserver", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotHost != "testserver" {
+		t.Errorf("expected the server to see Host: testserver, got %q", gotHost)
+	}
+	if stdout.String() != "ok\n" {
+		t.Errorf("expected the request to still reach the loopback server, got stdout %q", stdout.String())
+	}
+}
+
+// TestRun_HostDoesNotOverrideAnExplicitSNI asserts --sni still wins when
+// given alongside --host, since a caller who names both wants the Host
+// header and TLS server name to differ.
+func TestRun_HostDoesNotOverrideAnExplicitSNI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, r.Host)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-only", "--host", "testserver", "--sni", "other.example.com", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if stdout.String() != "testserver\n" {
+		t.Errorf("expected --host to still set the Host header, got stdout %q", stdout.String())
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_InternationalizedHostSendsPunycodeOverTheWire(t *testing.T) {
+	var gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	srvURL, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	port := srvURL.Port()
+	content := "GET http://café.example:" + port + "/\n\n"
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	resolveEntry := "xn--caf-dma.example:" + port + ":" + srvURL.Hostname()
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--resolve", resolveEntry, path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotHost != "xn--caf-dma.example:"+port {
+		t.Errorf("expected the server to see the punycode Host, got %q", gotHost)
+	}
+}
@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+)
+
+// interruptExitCode is the conventional shell exit code for a process
+// killed by SIGINT (128 + signal number 2), used here even though the
+// process itself exits cleanly rather than being killed by the signal.
+const interruptExitCode = 130
+
+// reportInterrupted prints how many of a batch's requests completed
+// before a SIGINT/SIGTERM aborted the rest, and returns the exit code
+// Run should return for it.
+func reportInterrupted(stderr io.Writer, completed, total int) int {
+	fmt.Fprintf(stderr, "http: interrupted, %d of %d requests completed\n", completed, total)
+	return interruptExitCode
+}
@@ -0,0 +1,18 @@
+package cli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReportInterrupted_PrintsCompletedCountAndReturns130(t *testing.T) {
+	var stderr bytes.Buffer
+	code := reportInterrupted(&stderr, 3, 10)
+	if code != 130 {
+		t.Errorf("expected exit code 130, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "3 of 10") {
+		t.Errorf("expected the report to mention 3 of 10 requests completed, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,92 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/adamijak/http/internal/validate"
+)
+
+// lintFileReport is one file's --lint findings, in file order.
+type lintFileReport struct {
+	Path     string   `json:"path"`
+	Errors   []string `json:"errors,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// runLint statically validates every request parsed from paths without
+// sending anything, reporting errors and warnings per file. It exits
+// non-zero if any file has an error, or, when strict is set, a warning.
+func runLint(paths []string, vopts validate.ValidateOptions, strict bool, format string, maxHeaderLines int, inputFormat string, stdout, stderr io.Writer) int {
+	expanded, err := expandFileArgs(paths)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	// Warnings are collected separately from Validate's fatal errors, so
+	// --strict can escalate them for the exit code without Validate
+	// itself refusing to inspect the rest of the request.
+	vopts.Strict = false
+
+	var reports []lintFileReport
+	problems := false
+	for _, path := range expanded {
+		report := lintFileReport{Path: path}
+
+		requests, warnings, err := parseFile(path, maxHeaderLines, inputFormat)
+		for _, w := range warnings {
+			report.Warnings = append(report.Warnings, w)
+		}
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+			reports = append(reports, report)
+			problems = true
+			continue
+		}
+
+		for _, req := range requests {
+			warnings, err := validate.Validate(req, vopts)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", requestLabel(req), err))
+				continue
+			}
+			for _, w := range warnings {
+				report.Warnings = append(report.Warnings, fmt.Sprintf("%s: %s", requestLabel(req), w))
+			}
+		}
+
+		if len(report.Errors) > 0 || (strict && len(report.Warnings) > 0) {
+			problems = true
+		}
+		reports = append(reports, report)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	} else {
+		for _, r := range reports {
+			fmt.Fprintf(stdout, "%s:\n", r.Path)
+			for _, e := range r.Errors {
+				fmt.Fprintf(stdout, "  error: %s\n", e)
+			}
+			for _, w := range r.Warnings {
+				fmt.Fprintf(stdout, "  warning: %s\n", w)
+			}
+			if len(r.Errors) == 0 && len(r.Warnings) == 0 {
+				fmt.Fprintln(stdout, "  ok")
+			}
+		}
+	}
+
+	if problems {
+		return 1
+	}
+	return 0
+}
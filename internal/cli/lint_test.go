@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_LintReportsCleanAndBrokenFilesInADirectory(t *testing.T) {
+	dir := t.TempDir()
+	cleanPath := filepath.Join(dir, "clean.http")
+	brokenPath := filepath.Join(dir, "broken.http")
+
+	if err := os.WriteFile(cleanPath, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// A relative-form target ("/orders") with no host is a fatal
+	// Validate error, not just a warning.
+	if err := os.WriteFile(brokenPath, []byte("GET /orders HTTP/1.1\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--lint", filepath.Join(dir, "*.http")}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit since one of the two files has an error")
+	}
+
+	out := stdout.String()
+	if !strings.Contains(out, "clean.http") || !strings.Contains(out, "ok") {
+		t.Errorf("expected clean.http to be reported ok, got %q", out)
+	}
+	if !strings.Contains(out, "broken.http") || !strings.Contains(out, "error:") {
+		t.Errorf("expected broken.http to be reported with an error, got %q", out)
+	}
+}
+
+func TestRun_LintJSONFormatIsMachineReadable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "clean.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--lint", "--lint-format", "json", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0 for a clean file, got %d, stderr=%s", code, stderr.String())
+	}
+
+	var reports []struct {
+		Path     string   `json:"path"`
+		Errors   []string `json:"errors,omitempty"`
+		Warnings []string `json:"warnings,omitempty"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &reports); err != nil {
+		t.Fatalf("stdout isn't valid JSON: %v\n%s", err, stdout.String())
+	}
+	if len(reports) != 1 || reports[0].Path != path {
+		t.Fatalf("unexpected reports: %+v", reports)
+	}
+	if len(reports[0].Errors) != 0 {
+		t.Errorf("expected no errors for a clean file, got %v", reports[0].Errors)
+	}
+}
+
+func TestRun_LintStrictEscalatesWarningsToFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	// A raw space in the target is a validation warning, not a fatal
+	// error, so plain --lint should pass but --lint --strict shouldn't.
+	if err := os.WriteFile(path, []byte("GET https://example.com/a b\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--lint", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0 without --strict, got %d, stdout=%s", code, stdout.String())
+	}
+
+	stdout.Reset()
+	code = Run([]string{"--lint", "--strict", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatalf("expected a non-zero exit with --lint --strict on a file with warnings, stdout=%s", stdout.String())
+	}
+}
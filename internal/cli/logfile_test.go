@@ -0,0 +1,67 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRun_LogFileWritesRequestAndResponseEvents covers --log-file's
+// JSON-lines output: a request event and a response event, the latter
+// carrying the status code and a populated duration, distinct from and
+// in addition to the normal stdout response output.
+func TestRun_LogFileWritesRequestAndResponseEvents(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	logPath := filepath.Join(dir, "events.jsonl")
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"--log-file", logPath, path}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	data, err := os.ReadFile(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 log lines, got %d: %q", len(lines), data)
+	}
+
+	var reqEvent, respEvent struct {
+		Type       string `json:"type"`
+		Method     string `json:"method"`
+		URL        string `json:"url"`
+		StatusCode int    `json:"status_code"`
+		DurationMS int64  `json:"duration_ms"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &reqEvent); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &respEvent); err != nil {
+		t.Fatal(err)
+	}
+	if reqEvent.Type != "request" || reqEvent.Method != "GET" {
+		t.Errorf("unexpected request event: %+v", reqEvent)
+	}
+	if respEvent.Type != "response" || respEvent.StatusCode != http.StatusTeapot {
+		t.Errorf("unexpected response event: %+v", respEvent)
+	}
+	if respEvent.DurationMS < 0 {
+		t.Errorf("expected a non-negative duration, got %d", respEvent.DurationMS)
+	}
+}
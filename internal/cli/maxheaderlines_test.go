@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_MaxHeaderLinesRejectsOversizedHeaderBlock(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("GET https://example.com/\n")
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&b, "X-Header-%d: value\n", i)
+	}
+	b.WriteString("\n")
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--max-header-lines", "5", "--no-send", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for a header block over --max-header-lines")
+	}
+	if !strings.Contains(stderr.String(), "max-header-lines") {
+		t.Errorf("expected a --max-header-lines error, got %q", stderr.String())
+	}
+}
+
+func TestRun_FoldedHeaderWarningIsPrintedAndRequestStillSends(t *testing.T) {
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Long")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	body := "GET " + srv.URL + "\nX-Long: first\n second\n\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "folded") {
+		t.Errorf("expected a folding warning on stderr, got %q", stderr.String())
+	}
+	if gotHeader != "first second" {
+		t.Errorf("expected the folded header value to reach the server, got %q", gotHeader)
+	}
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRun_MaxResponseTimeFailsWhenTheResponseIsTooSlow(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--max-response-time", "10ms", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatalf("expected a non-zero exit, got 0, stdout=%s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "ok") {
+		t.Errorf("expected the response to still be printed, got %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), "--max-response-time") {
+		t.Errorf("expected the error to mention --max-response-time, got %q", stderr.String())
+	}
+}
+
+func TestRun_MaxResponseTimePassesWhenTheResponseIsFastEnough(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--max-response-time", "5s", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+}
+
+func TestRun_InvalidMaxResponseTimeIsRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--max-response-time", "banana", path}, nil, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d, stderr=%s", code, stderr.String())
+	}
+}
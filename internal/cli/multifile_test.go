@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_MultipleFilesShareCookieJarAndSendInArgumentOrder(t *testing.T) {
+	var gotCookie string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123"})
+			fmt.Fprint(w, "logged in")
+		case "/me":
+			gotCookie = r.Header.Get("Cookie")
+			fmt.Fprint(w, "whoami")
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	authPath := filepath.Join(dir, "auth.http")
+	usersPath := filepath.Join(dir, "users.http")
+	if err := os.WriteFile(authPath, []byte("### login\nGET "+srv.URL+"/login\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(usersPath, []byte("### whoami\nGET "+srv.URL+"/me\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	jarPath := filepath.Join(dir, "cookies.json")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--cookie-jar", jarPath, authPath, usersPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(gotCookie, "session=abc123") {
+		t.Errorf("expected the second file's request to carry the cookie set by the first, got Cookie=%q", gotCookie)
+	}
+
+	out := stdout.String()
+	if !strings.Contains(out, "auth.http: login") {
+		t.Errorf("expected a per-file header for auth.http, got %q", out)
+	}
+	if !strings.Contains(out, "users.http: whoami") {
+		t.Errorf("expected a per-file header for users.http, got %q", out)
+	}
+	if strings.Index(out, "auth.http: login") > strings.Index(out, "users.http: whoami") {
+		t.Errorf("expected auth.http's request to be printed before users.http's, got %q", out)
+	}
+}
+
+func TestRun_MissingFileAmongMultipleArgsErrors(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "a.http")
+	if err := os.WriteFile(existing, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "does-not-exist.http")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--no-send", existing, missing}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for a missing file")
+	}
+}
@@ -0,0 +1,68 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_NoBodyStripsBodyAndContentHeadersBeforeSending(t *testing.T) {
+	var gotContentLength, gotContentType string
+	var gotBody []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentLength = r.Header.Get("Content-Length")
+		gotContentType = r.Header.Get("Content-Type")
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	content := "POST " + srv.URL + "/\nContent-Type: application/json\n\n{\"a\":1}\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--no-body", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if len(gotBody) != 0 {
+		t.Errorf("expected no body to be sent, got %q", gotBody)
+	}
+	// net/http itself adds "Content-Length: 0" for a request with an
+	// empty body; what matters is that the tool's own Content-Length
+	// (sized for the original body) isn't sent.
+	if gotContentLength != "" && gotContentLength != "0" {
+		t.Errorf("expected no non-zero Content-Length header, got %q", gotContentLength)
+	}
+	if gotContentType != "" {
+		t.Errorf("expected no Content-Type header, got %q", gotContentType)
+	}
+}
+
+func TestRun_NoBodyReflectsInNoSendOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	content := "POST https://example.com/\nContent-Type: application/json\n\n{\"a\":1}\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--no-send", "--no-body", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	out := stdout.String()
+	if strings.Contains(out, "Content-Type") || strings.Contains(out, "Content-Length") {
+		t.Errorf("expected --no-send output to omit stripped headers, got %q", out)
+	}
+	if strings.Contains(out, `{"a":1}`) {
+		t.Errorf("expected --no-send output to omit the stripped body, got %q", out)
+	}
+}
@@ -0,0 +1,25 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseNTLMUser splits a "--user domain\user:pass" value into its
+// domain, username, and password parts for --ntlm. The domain is
+// optional; a value with no backslash is treated as a bare username.
+func parseNTLMUser(spec string) (domain, user, password string, err error) {
+	userPart, password, ok := strings.Cut(spec, ":")
+	if !ok {
+		return "", "", "", fmt.Errorf("--user must be in domain\\user:pass form")
+	}
+	if d, u, ok := strings.Cut(userPart, `\`); ok {
+		domain, user = d, u
+	} else {
+		user = userPart
+	}
+	if user == "" {
+		return "", "", "", fmt.Errorf("--user must include a username")
+	}
+	return domain, user, password, nil
+}
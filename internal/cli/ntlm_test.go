@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_NTLMRequiresUser(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--ntlm", path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "--ntlm requires --user") {
+		t.Errorf("expected an --ntlm/--user error, got %q", stderr.String())
+	}
+}
+
+func TestRun_UserRequiresNTLM(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--user", `EXAMPLE\alice:hunter2`, path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "--user requires --ntlm") {
+		t.Errorf("expected a --user/--ntlm error, got %q", stderr.String())
+	}
+}
+
+func TestRun_NTLMAuthenticatesAgainstAChallengingServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if !strings.HasPrefix(authorization, "NTLM ") {
+			w.Header().Set("Www-Authenticate", "NTLM")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		_, blob, _ := strings.Cut(authorization, " ")
+		raw, err := base64.StdEncoding.DecodeString(blob)
+		if err != nil {
+			t.Fatalf("malformed NTLM message: %v", err)
+		}
+		switch binary.LittleEndian.Uint32(raw[8:12]) {
+		case 1:
+			challenge := make([]byte, 48)
+			copy(challenge[0:8], "NTLMSSP\x00")
+			binary.LittleEndian.PutUint32(challenge[8:12], 2)
+			w.Header().Set("Www-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(challenge))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--ntlm", "--user", `EXAMPLE\alice:hunter2`, path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("ok")) {
+		t.Errorf("expected the authenticated response body to print, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+)
+
+// openapiParamList collects repeated "name=value" --openapi-param flag
+// occurrences, filling in an OpenAPI operation's path/query parameters.
+type openapiParamList []string
+
+func (l *openapiParamList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *openapiParamList) Set(s string) error {
+	name, _, ok := strings.Cut(s, "=")
+	if !ok || name == "" {
+		return fmt.Errorf("invalid --openapi-param value %q, expected name=value", s)
+	}
+	*l = append(*l, s)
+	return nil
+}
+
+func (l openapiParamList) toMap() map[string]string {
+	m := make(map[string]string, len(l))
+	for _, kv := range l {
+		name, value, _ := strings.Cut(kv, "=")
+		m[name] = value
+	}
+	return m
+}
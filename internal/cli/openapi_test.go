@@ -0,0 +1,86 @@
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_OpenAPIBuildsAndSendsARequestForAnOperation(t *testing.T) {
+	var gotMethod, gotPath, gotQuery string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotPath = r.URL.Path
+		gotQuery = r.URL.RawQuery
+	}))
+	defer srv.Close()
+
+	spec := map[string]any{
+		"openapi": "3.0.0",
+		"servers": []map[string]string{{"url": srv.URL}},
+		"paths": map[string]any{
+			"/users/{id}": map[string]any{
+				"get": map[string]any{
+					"operationId": "getUser",
+					"parameters": []map[string]any{
+						{"name": "id", "in": "path", "required": true},
+						{"name": "verbose", "in": "query", "required": false},
+					},
+				},
+			},
+		},
+	}
+	data, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	specPath := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(specPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{
+		"--openapi", specPath,
+		"--operation", "getUser",
+		"--openapi-param", "id=42",
+		"--openapi-param", "verbose=true",
+	}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotMethod != "GET" {
+		t.Errorf("expected method GET, got %q", gotMethod)
+	}
+	if gotPath != "/users/42" {
+		t.Errorf("expected path /users/42, got %q", gotPath)
+	}
+	if gotQuery != "verbose=true" {
+		t.Errorf("expected query verbose=true, got %q", gotQuery)
+	}
+}
+
+func TestRun_OpenAPIWithoutOperationErrors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--openapi", "spec.json"}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --openapi without --operation")
+	}
+}
+
+func TestRun_OpenAPIWithFileArgumentErrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--openapi", "spec.json", "--operation", "getUser", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --openapi combined with a .http file argument")
+	}
+}
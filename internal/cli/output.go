@@ -0,0 +1,31 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// writeOutput saves resp's body to path for --output. Under --resume, a
+// 206 Partial Content response is appended to whatever's already there;
+// any other status (in particular a 200, which means the server ignored
+// the Range header) restarts the file from scratch.
+func writeOutput(path string, resp *models.HTTPResponse, resume bool) error {
+	if resume && resp.StatusCode == http.StatusPartialContent {
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+		if err != nil {
+			return fmt.Errorf("--output: %w", err)
+		}
+		defer f.Close()
+		if _, err := f.Write(resp.RawBody); err != nil {
+			return fmt.Errorf("--output: %w", err)
+		}
+		return nil
+	}
+	if err := os.WriteFile(path, resp.RawBody, 0o644); err != nil {
+		return fmt.Errorf("--output: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// TestRun_ParallelSendsAllRequestsAndPrintsThemInOrder sends ten
+// independent requests with --parallel 4 against a testserver and
+// asserts every one arrives, and that stdout still lists their
+// responses in input order despite running concurrently.
+func TestRun_ParallelSendsAllRequestsAndPrintsThemInOrder(t *testing.T) {
+	var received atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		fmt.Fprintf(w, "ok:%s", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	var contents strings.Builder
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&contents, "### req-%d\nGET %s/%d\n\n", i, srv.URL, i)
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "batch.http")
+	if err := os.WriteFile(path, []byte(contents.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--parallel", "4", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if got := received.Load(); got != 10 {
+		t.Fatalf("expected the server to see all 10 requests, got %d", got)
+	}
+
+	var lastIndex = -1
+	for i := 0; i < 10; i++ {
+		idx := strings.Index(stdout.String(), "ok:/"+strconv.Itoa(i))
+		if idx < 0 {
+			t.Fatalf("expected response %d in stdout, got %q", i, stdout.String())
+		}
+		if idx < lastIndex {
+			t.Errorf("expected response %d to print after response %d, got %q", i, i-1, stdout.String())
+		}
+		lastIndex = idx
+	}
+}
+
+func TestRun_ParallelAndCookieJarAreMutuallyExclusive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--parallel", "4", "--cookie-jar", filepath.Join(dir, "jar.json"), path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "mutually exclusive") {
+		t.Errorf("expected an error mentioning mutual exclusivity, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// printParts is which sections of the request and response --print
+// shows, mirroring HTTPie's -p/--print: H request headers, B request
+// body, h response headers, b response body.
+type printParts struct {
+	requestHeaders  bool
+	requestBody     bool
+	responseHeaders bool
+	responseBody    bool
+}
+
+// defaultPrintParts is what prints when --print isn't given: the
+// response's status line, headers, and body, matching the CLI's
+// long-standing default output.
+var defaultPrintParts = printParts{responseHeaders: true, responseBody: true}
+
+// showsHeaders reports whether p prints either side's headers, used to
+// decide whether the "### <request>" separator line is worth printing.
+func (p printParts) showsHeaders() bool {
+	return p.requestHeaders || p.responseHeaders
+}
+
+// parsePrintParts parses a --print spec, a combination of the
+// characters H (request headers), B (request body), h (response
+// headers), and b (response body), in any order and combination.
+func parsePrintParts(spec string) (printParts, error) {
+	var p printParts
+	for _, c := range spec {
+		switch c {
+		case 'H':
+			p.requestHeaders = true
+		case 'B':
+			p.requestBody = true
+		case 'h':
+			p.responseHeaders = true
+		case 'b':
+			p.responseBody = true
+		default:
+			return printParts{}, fmt.Errorf("invalid --print character %q, expected any combination of H, B, h, b", c)
+		}
+	}
+	return p, nil
+}
+
+// printRequestAndResponse prints req and resp according to parts,
+// shared by the normal per-request output and --fail-with-body's
+// print-then-fail path.
+func printRequestAndResponse(w io.Writer, req *models.Request, resp *models.HTTPResponse, parts printParts, headerCase string, color bool, theme string, timing bool, trimBytes, trimLines int, sortHeaders bool) {
+	if parts.showsHeaders() {
+		fmt.Fprintf(w, "### %s\n", fileRequestHeader(req))
+	}
+	req.Print(w, headerCase, models.RequestPrintOptions{Headers: parts.requestHeaders, Body: parts.requestBody, AlignHeaders: sortHeaders})
+	if parts.responseHeaders || parts.responseBody {
+		resp.Print(w, models.PrintOptions{
+			Timing:       timing && parts.responseHeaders,
+			Color:        color && parts.responseHeaders,
+			Theme:        theme,
+			BodyOnly:     parts.responseBody && !parts.responseHeaders,
+			HeadersOnly:  parts.responseHeaders && !parts.responseBody,
+			TrimBytes:    trimBytes,
+			TrimLines:    trimLines,
+			AlignHeaders: sortHeaders,
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_PrintHhShowsBothHeaderSetsOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Reply", "yes")
+		fmt.Fprint(w, "hello world")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	body := "GET " + srv.URL + "/\nX-Request: yes\n\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--print", "Hh", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	got := stdout.String()
+	if !bytes.Contains([]byte(got), []byte("X-Request: yes")) {
+		t.Errorf("expected request headers in output, got %q", got)
+	}
+	if !bytes.Contains([]byte(got), []byte("X-Reply: yes")) {
+		t.Errorf("expected response headers in output, got %q", got)
+	}
+	if bytes.Contains([]byte(got), []byte("hello world")) {
+		t.Errorf("expected the response body to be suppressed, got %q", got)
+	}
+}
+
+func TestRun_PrintBShowsResponseBodyOnly(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Reply", "yes")
+		fmt.Fprint(w, "hello world")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--print", "b", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if got := stdout.String(); got != "hello world\n" {
+		t.Errorf("expected only the response body, got %q", got)
+	}
+}
+
+func TestRun_PrintAndBodyOnlyAreMutuallyExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.invalid/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--print", "b", "--body-only", path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d", code)
+	}
+}
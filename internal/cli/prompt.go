@@ -0,0 +1,80 @@
+package cli
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/adamijak/http/internal/chain"
+	"github.com/adamijak/http/internal/models"
+)
+
+// secretVariablePattern names variables --prompt hides input for while
+// they're typed: anything with "token", "password", or "secret"
+// anywhere in the name, case-insensitively.
+var secretVariablePattern = regexp.MustCompile(`(?i)token|password|secret`)
+
+// promptForUndefinedVariables resolves req against store exactly like
+// resolveRequest, except that hitting an undefined "{{name}}"
+// placeholder prompts for a value on stdin/stderr (hiding it while
+// typed when name matches secretVariablePattern and stdin is a real
+// terminal) instead of failing the request. A value entered this way is
+// stored in store, so later requests in the same run referencing the
+// same name reuse it instead of prompting again. Any other resolution
+// failure, or a read error while prompting, is returned as-is.
+func promptForUndefinedVariables(req *models.Request, store chain.Store, stdin io.Reader, stderr io.Writer) error {
+	reader := bufio.NewReader(stdin)
+	for {
+		err := resolveRequest(req, store)
+		var undefined *chain.UndefinedVariableError
+		if !errors.As(err, &undefined) {
+			return err
+		}
+
+		value, err := promptValue(reader, stdin, stderr, undefined.Name)
+		if err != nil {
+			return fmt.Errorf("prompting for {{%s}}: %w", undefined.Name, err)
+		}
+		store[undefined.Name] = value
+	}
+}
+
+// promptValue writes a "name: " prompt to stderr and reads one line
+// from reader, hiding the typed characters first if name looks like a
+// token/password/secret and stdin is a terminal we can toggle echo on;
+// disableEcho failing (piped input, an unsupported platform) just falls
+// back to a visible prompt rather than aborting the whole run.
+func promptValue(reader *bufio.Reader, stdin io.Reader, stderr io.Writer, name string) (string, error) {
+	fmt.Fprintf(stderr, "%s: ", name)
+
+	hide := secretVariablePattern.MatchString(name)
+	if f, ok := stdin.(*os.File); ok && hide && isTerminalReader(f) {
+		if restore, err := disableEcho(f.Fd()); err == nil {
+			defer func() {
+				restore()
+				fmt.Fprintln(stderr)
+			}()
+		}
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// isTerminalReader is isTerminal's counterpart for an input file,
+// used by --prompt to decide whether hiding a secret's input via
+// disableEcho is worth attempting.
+func isTerminalReader(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
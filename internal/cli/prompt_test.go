@@ -0,0 +1,52 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_PromptSubstitutesAnEnteredValueForAnUndefinedVariable(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	body := "GET " + srv.URL + "/\nAuthorization: Bearer {{token}}\n\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := strings.NewReader("s3cr3t\n")
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--prompt", path}, stdin, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotAuth != "Bearer s3cr3t" {
+		t.Errorf("expected Authorization: Bearer s3cr3t, got %q", gotAuth)
+	}
+	if !strings.Contains(stderr.String(), "token: ") {
+		t.Errorf("expected a prompt for %q on stderr, got %q", "token", stderr.String())
+	}
+}
+
+func TestRun_PromptAndReplAreMutuallyExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--prompt", "--repl", path}, strings.NewReader(""), &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d, stderr=%s", code, stderr.String())
+	}
+}
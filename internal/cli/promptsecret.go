@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// disableEcho turns off terminal echo on the file descriptor fd, so
+// --prompt can read a token/password/secret-looking value without it
+// appearing on screen, and returns a func that restores the original
+// settings. This talks to the kernel directly via ioctl (TCGETS/TCSETS)
+// instead of a package like golang.org/x/term, since the project has no
+// third-party dependencies; it only works where those ioctls exist
+// (Linux), which is the only platform this repo otherwise builds for.
+func disableEcho(fd uintptr) (restore func(), err error) {
+	var oldState syscall.Termios
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCGETS, uintptr(unsafe.Pointer(&oldState))); errno != 0 {
+		return nil, errno
+	}
+
+	newState := oldState
+	newState.Lflag &^= syscall.ECHO
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCSETS, uintptr(unsafe.Pointer(&newState))); errno != 0 {
+		return nil, errno
+	}
+
+	return func() {
+		syscall.Syscall(syscall.SYS_IOCTL, fd, syscall.TCSETS, uintptr(unsafe.Pointer(&oldState)))
+	}, nil
+}
@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// keyValueList collects repeated "key=value" flag occurrences, such as
+// --query, and knows how to merge itself into a URL's query string.
+type keyValueList []string
+
+func (l *keyValueList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *keyValueList) Set(s string) error {
+	key, value, ok := strings.Cut(s, "=")
+	if !ok {
+		return fmt.Errorf("invalid --query value %q, expected key=value", s)
+	}
+	*l = append(*l, key+"="+value)
+	return nil
+}
+
+// applyTo merges every collected key=value pair into u's query string,
+// preserving any parameters already present and URL-encoding the added
+// values.
+func (l keyValueList) applyTo(u *url.URL) {
+	if len(l) == 0 {
+		return
+	}
+	q := u.Query()
+	for _, kv := range l {
+		key, value, _ := strings.Cut(kv, "=")
+		q.Add(key, value)
+	}
+	u.RawQuery = q.Encode()
+}
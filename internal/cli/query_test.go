@@ -0,0 +1,46 @@
+package cli
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestKeyValueListApplyTo_MergesExistingQuery(t *testing.T) {
+	u, err := url.Parse("https://example.com/path?a=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := keyValueList{"b=2"}
+	list.applyTo(u)
+
+	got := u.Query()
+	if got.Get("a") != "1" {
+		t.Errorf("expected existing param a=1 to be preserved, got %q", got.Get("a"))
+	}
+	if got.Get("b") != "2" {
+		t.Errorf("expected new param b=2, got %q", got.Get("b"))
+	}
+}
+
+func TestKeyValueListApplyTo_EncodesSpecialCharacters(t *testing.T) {
+	u, err := url.Parse("https://example.com/path")
+	if err != nil {
+		t.Fatal(err)
+	}
+	list := keyValueList{"q=hello world&more"}
+	list.applyTo(u)
+
+	if got := u.Query().Get("q"); got != "hello world&more" {
+		t.Errorf("expected decoded value %q, got %q", "hello world&more", got)
+	}
+	if u.RawQuery != "q=hello+world%26more" {
+		t.Errorf("expected encoded raw query, got %q", u.RawQuery)
+	}
+}
+
+func TestKeyValueListSet_RejectsMissingEquals(t *testing.T) {
+	var list keyValueList
+	if err := list.Set("noequals"); err == nil {
+		t.Error("expected error for value without '='")
+	}
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// contentTypeAliases expands a --content-type/--type shorthand to its
+// full MIME type; a value that isn't a known alias is used as-is, so
+// e.g. --type application/vnd.api+json still works.
+var contentTypeAliases = map[string]string{
+	"json": "application/json",
+	"form": "application/x-www-form-urlencoded",
+	"xml":  "application/xml",
+	"text": "text/plain",
+}
+
+// resolveContentType expands alias via contentTypeAliases, falling back
+// to alias itself when it isn't a recognized shorthand.
+func resolveContentType(alias string) string {
+	if full, ok := contentTypeAliases[alias]; ok {
+		return full
+	}
+	return alias
+}
+
+// looksLikeURL reports whether arg is an absolute http(s) URL rather
+// than a .http file path, so a bare "http https://example.com/api"
+// invocation can skip the file-parsing pipeline entirely.
+func looksLikeURL(arg string) bool {
+	return strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "https://")
+}
+
+// quickRequest builds a Request directly from a bare URL positional
+// argument, for curl-like one-off calls that don't warrant writing a
+// .http file. method defaults to GET when empty. jsonBody, if non-empty,
+// must be well-formed JSON; it becomes the body and sets Content-Type to
+// application/json. contentType, if non-empty, is expanded via
+// resolveContentType and sets Content-Type, overriding --json-body's
+// default. headers are applied last ("Name: value" per entry), so an
+// explicit -H always wins.
+func quickRequest(rawURL, method string, headers headerList, jsonBody, contentType string) (*models.Request, error) {
+	if method == "" {
+		method = http.MethodGet
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("http: invalid URL %q: %w", rawURL, err)
+	}
+
+	req := models.New()
+	req.Method = strings.ToUpper(method)
+	req.RawTarget = rawURL
+	req.URL = u
+
+	if jsonBody != "" {
+		if !json.Valid([]byte(jsonBody)) {
+			return nil, fmt.Errorf("http: --json-body is not well-formed JSON: %q", jsonBody)
+		}
+		req.Body = jsonBody
+		req.SetHeader("Content-Type", "application/json")
+	}
+	if contentType != "" {
+		req.SetHeader("Content-Type", resolveContentType(contentType))
+	}
+	headers.applyTo(req)
+	return req, nil
+}
@@ -0,0 +1,130 @@
+package cli
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRun_BareURLBuildsAGETRequest(t *testing.T) {
+	var gotMethod, gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotHost = r.Host
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{srv.URL + "/api"}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotMethod != http.MethodGet {
+		t.Errorf("expected a GET request, got %q", gotMethod)
+	}
+	if gotHost == "" {
+		t.Error("expected an auto Host header to reach the server")
+	}
+	if !bytes.Contains(stdout.Bytes(), []byte("ok")) {
+		t.Errorf("expected the response body to print, got %q", stdout.String())
+	}
+}
+
+func TestRun_BareURLWithMethodAndDataFlags(t *testing.T) {
+	var gotMethod, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"-X", "POST", "--data", "name=widget", srv.URL + "/widgets"}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotMethod != http.MethodPost {
+		t.Errorf("expected a POST request, got %q", gotMethod)
+	}
+	if gotBody != "name=widget" {
+		t.Errorf("expected the --data field in the body, got %q", gotBody)
+	}
+}
+
+func TestRun_JSONBodySetsBodyAndContentType(t *testing.T) {
+	var gotContentType, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentType = r.Header.Get("Content-Type")
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"-X", "POST", "--json-body", `{"k":"v"}`, srv.URL}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotContentType != "application/json" {
+		t.Errorf("expected Content-Type: application/json, got %q", gotContentType)
+	}
+	if gotBody != `{"k":"v"}` {
+		t.Errorf("expected the --json-body value as the body, got %q", gotBody)
+	}
+}
+
+func TestRun_JSONBodyRejectsMalformedJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--json-body", `{not json}`, "http://example.com/"}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for malformed --json-body")
+	}
+}
+
+func TestRun_ContentTypeAliases(t *testing.T) {
+	for alias, want := range map[string]string{
+		"json": "application/json",
+		"form": "application/x-www-form-urlencoded",
+		"xml":  "application/xml",
+		"text": "text/plain",
+	} {
+		t.Run(alias, func(t *testing.T) {
+			var gotContentType string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotContentType = r.Header.Get("Content-Type")
+			}))
+			defer srv.Close()
+
+			var stdout, stderr bytes.Buffer
+			code := Run([]string{"--type", alias, srv.URL}, nil, &stdout, &stderr)
+			if code != 0 {
+				t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+			}
+			if gotContentType != want {
+				t.Errorf("expected Content-Type %q for alias %q, got %q", want, alias, gotContentType)
+			}
+		})
+	}
+}
+
+func TestRun_BareURLWithHeaderFlag(t *testing.T) {
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("X-Api-Key")
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"-H", "X-Api-Key: secret", srv.URL}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotHeader != "secret" {
+		t.Errorf("expected the -H header to reach the server, got %q", gotHeader)
+	}
+}
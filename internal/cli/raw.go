@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"time"
+)
+
+// runRaw sends path's bytes over a TCP connection to target completely
+// unchanged: no parsing, no validation, no Host/Content-Length
+// injection. It's for reproducing exact wire-level bugs (malformed
+// requests, smuggling attempts) where even --no-send's well-formed
+// request model gets in the way.
+func runRaw(path, target string, timeout time.Duration, stdout, stderr io.Writer) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	conn, err := net.DialTimeout("tcp", target, timeout)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	if _, err := conn.Write(data); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	// A raw response has no framing this mode understands (it might not
+	// even be well-formed HTTP), so read until the deadline or the
+	// server closes the connection, and print whatever came back either
+	// way rather than treating the deadline as a failure.
+	resp, err := io.ReadAll(conn)
+	var netErr net.Error
+	if err != nil && !(errors.As(err, &netErr) && netErr.Timeout()) {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	stdout.Write(resp)
+	return 0
+}
@@ -0,0 +1,105 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// acceptOnceEcho listens on an ephemeral local port, and for the single
+// connection it accepts, reads until the request's blank-line
+// terminator (a real HTTP server would reject the unusual spacing this
+// test sends; this stub just proves the exact bytes arrived) and
+// replies with a fixed response.
+func acceptOnceEcho(t *testing.T) (addr string, gotRequest chan []byte) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gotRequest = make(chan []byte, 1)
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		var buf bytes.Buffer
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			buf.WriteString(line)
+			if err != nil || line == "\r\n" || line == "\n" {
+				break
+			}
+		}
+		gotRequest <- buf.Bytes()
+		conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"))
+	}()
+	return ln.Addr().String(), gotRequest
+}
+
+func TestRun_RawSendsBytesVerbatimWithUnusualSpacing(t *testing.T) {
+	addr, gotRequest := acceptOnceEcho(t)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "raw.txt")
+	// Deliberately unusual spacing (extra space before the HTTP
+	// version) that the normal parser would reject or normalize.
+	raw := "GET  /weird   HTTP/1.1\r\nHost: example.com\r\n\r\n"
+	if err := os.WriteFile(path, []byte(raw), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--raw", "--target", addr, path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	select {
+	case got := <-gotRequest:
+		if string(got) != raw {
+			t.Errorf("server received %q, want %q", got, raw)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the server to receive the raw request")
+	}
+
+	if !bytes.Contains(stdout.Bytes(), []byte("HTTP/1.1 200 OK")) {
+		t.Errorf("expected the raw response bytes on stdout, got %q", stdout.String())
+	}
+}
+
+func TestRun_RawRequiresTarget(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "raw.txt")
+	if err := os.WriteFile(path, []byte("GET / HTTP/1.1\r\n\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--raw", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --raw without --target")
+	}
+}
+
+func TestRun_RawTakesExactlyOneFile(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	os.WriteFile(a, []byte("GET / HTTP/1.1\r\n\r\n"), 0o600)
+	os.WriteFile(b, []byte("GET / HTTP/1.1\r\n\r\n"), 0o600)
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--raw", "--target", "127.0.0.1:1", a, b}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --raw given more than one file")
+	}
+}
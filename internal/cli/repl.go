@@ -0,0 +1,116 @@
+package cli
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/adamijak/http/internal/client"
+	"github.com/adamijak/http/internal/httpfile"
+	"github.com/adamijak/http/internal/models"
+	"github.com/adamijak/http/internal/render"
+	"github.com/adamijak/http/internal/validate"
+)
+
+// runRepl drives --repl's interactive loop: each line from stdin is one
+// command applied to req, which is otherwise the same
+// prepareRequest/Validate/client.Do pipeline the normal one-shot send
+// path uses. EOF (a closed pipe, or a non-interactive stdin with
+// nothing left to read) ends the loop the same as "quit", so scripting
+// a REPL session, or piping in nothing, exits cleanly rather than
+// hanging.
+func runRepl(stdin io.Reader, stdout, stderr io.Writer, req *models.Request, opts client.Options, vopts validate.ValidateOptions) int {
+	scanner := bufio.NewScanner(stdin)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "set":
+			if err := replSet(req, fields[1:]); err != nil {
+				fmt.Fprintln(stderr, "repl:", err)
+			}
+		case "send":
+			replSend(stdout, stderr, req, opts, vopts)
+		case "show":
+			render.Request(stdout, req, opts.Proxy != nil, opts.HeaderCase)
+		case "save":
+			if len(fields) != 2 {
+				fmt.Fprintln(stderr, "repl: usage: save <file.http>")
+				continue
+			}
+			if err := replSave(fields[1], req); err != nil {
+				fmt.Fprintln(stderr, "repl:", err)
+			}
+		case "quit", "exit":
+			return 0
+		default:
+			fmt.Fprintf(stderr, "repl: unknown command %q\n", fields[0])
+		}
+	}
+	return 0
+}
+
+// replSet applies a "set header <name> <value>", "set method <method>",
+// or "set body <text...>" command to req.
+func replSet(req *models.Request, args []string) error {
+	if len(args) < 2 {
+		return fmt.Errorf("usage: set header <name> <value> | set method <method> | set body <text>")
+	}
+	switch args[0] {
+	case "header":
+		if len(args) != 3 {
+			return fmt.Errorf("usage: set header <name> <value>")
+		}
+		req.SetHeader(args[1], args[2])
+	case "method":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: set method <method>")
+		}
+		req.Method = strings.ToUpper(args[1])
+	case "body":
+		req.Body = strings.Join(args[1:], " ")
+		// The old Content-Length no longer matches; let validate.Validate
+		// recompute it against the new body on the next "send".
+		req.DeleteHeader("Content-Length")
+	default:
+		return fmt.Errorf("unknown \"set\" target %q, expected header, method, or body", args[0])
+	}
+	return nil
+}
+
+// replSend validates and sends req exactly like the normal one-shot
+// path, printing the response (or any warning/error) to stdout/stderr,
+// without ending the REPL loop either way.
+func replSend(stdout, stderr io.Writer, req *models.Request, opts client.Options, vopts validate.ValidateOptions) {
+	warnings, err := validate.Validate(req, vopts)
+	if err != nil {
+		fmt.Fprintln(stderr, "repl:", err)
+		return
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(stderr, "warning: %s\n", w)
+	}
+
+	resp, err := client.New(opts).Do(req)
+	if err != nil {
+		fmt.Fprintln(stderr, "repl:", err)
+		return
+	}
+	resp.Print(stdout, models.PrintOptions{})
+}
+
+// replSave writes req back out to path in .http format, so a session's
+// edits can be kept for a later non-interactive run.
+func replSave(path string, req *models.Request) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return httpfile.Write(f, req)
+}
@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_ReplSendsWithEditsAppliedInOrder(t *testing.T) {
+	var gotMethod, gotHeader, gotBody string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotHeader = r.Header.Get("X-Trace")
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		fmt.Fprint(w, "ack")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	commands := strings.NewReader(strings.Join([]string{
+		"set method POST",
+		"set header X-Trace on",
+		"set body hello",
+		"send",
+		"quit",
+	}, "\n") + "\n")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--repl", path}, commands, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotMethod != "POST" {
+		t.Errorf("expected method POST, got %q", gotMethod)
+	}
+	if gotHeader != "on" {
+		t.Errorf("expected X-Trace: on, got %q", gotHeader)
+	}
+	if gotBody != "hello" {
+		t.Errorf("expected body %q, got %q", "hello", gotBody)
+	}
+	if !strings.Contains(stdout.String(), "ack") {
+		t.Errorf("expected the response to be printed, got %q", stdout.String())
+	}
+}
+
+func TestRun_ReplShowPrintsCurrentRequestWithoutSending(t *testing.T) {
+	var sent bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	commands := strings.NewReader("show\nquit\n")
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--repl", path}, commands, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if sent {
+		t.Error("expected \"show\" not to send the request")
+	}
+	if !strings.Contains(stdout.String(), "GET / HTTP/1.1") {
+		t.Errorf("expected the request line, got %q", stdout.String())
+	}
+}
+
+func TestRun_ReplSaveWritesEditedRequestToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	savePath := filepath.Join(t.TempDir(), "saved.http")
+
+	commands := strings.NewReader("set method DELETE\nsave " + savePath + "\nquit\n")
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--repl", path}, commands, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	saved, err := os.ReadFile(savePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(saved), "DELETE https://example.com/") {
+		t.Errorf("expected the saved file to reflect the edit, got %q", string(saved))
+	}
+}
+
+func TestRun_ReplWithEmptyStdinExitsCleanly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--repl", path}, strings.NewReader(""), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0 for empty (non-TTY) stdin, got %d, stderr=%s", code, stderr.String())
+	}
+}
+
+func TestRun_ReplWithNilStdinExitsCleanly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--repl", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0 for nil stdin, got %d, stderr=%s", code, stderr.String())
+	}
+}
+
+func TestRun_ReplAndNoSendAreMutuallyExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--repl", "--no-send", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --repl combined with --no-send")
+	}
+}
@@ -0,0 +1,56 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_RecordThenReplayReproducesTheResponseOffline(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"ok":true}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/widgets\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	exchangePath := filepath.Join(t.TempDir(), "exchange.json")
+
+	var recordStdout, recordStderr bytes.Buffer
+	code := Run([]string{"--record", exchangePath, path}, nil, &recordStdout, &recordStderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0 recording, got %d, stderr=%s", code, recordStderr.String())
+	}
+	if requests != 1 {
+		t.Fatalf("expected 1 request to reach the server while recording, got %d", requests)
+	}
+
+	srv.Close() // prove --replay never touches the network
+
+	var replayStdout, replayStderr bytes.Buffer
+	code = Run([]string{"--replay", exchangePath}, nil, &replayStdout, &replayStderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0 replaying, got %d, stderr=%s", code, replayStderr.String())
+	}
+	if !strings.Contains(replayStdout.String(), "200 OK") || !strings.Contains(replayStdout.String(), `{"ok":true}`) {
+		t.Errorf("expected the replayed response, got %q", replayStdout.String())
+	}
+}
+
+func TestRun_ReplayRejectsAMissingFile(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--replay", filepath.Join(t.TempDir(), "missing.json")}, nil, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d, stderr=%s", code, stderr.String())
+	}
+}
@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_RequestTargetAbsoluteSendsAnAbsoluteURIRequestLine(t *testing.T) {
+	var gotRequestURI string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRequestURI = r.RequestURI
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/path\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--request-target", "absolute", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.HasPrefix(gotRequestURI, "http://") {
+		t.Errorf("expected an absolute-URI request line, got %q", gotRequestURI)
+	}
+}
+
+func TestRun_NoSendWithRequestTargetAbsolutePrintsAbsoluteForm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/path?a=1\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--request-target", "absolute", "--no-send", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "GET http://example.com/path?a=1 ") {
+		t.Errorf("expected absolute-form request line, got %q", stdout.String())
+	}
+}
+
+func TestRun_RequestTargetAsteriskForcesAsteriskForm(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("OPTIONS http://example.com/some/path\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--request-target", "asterisk", "--no-send", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.HasPrefix(stdout.String(), "OPTIONS * ") {
+		t.Errorf("expected asterisk-form request line, got %q", stdout.String())
+	}
+}
+
+func TestRun_RequestTargetAsteriskRejectsANonOptionsRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--request-target", "asterisk", "--no-send", path}, nil, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d, stderr=%s", code, stderr.String())
+	}
+}
+
+func TestRun_RequestTargetAuthorityRejectsANonConnectRequest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--request-target", "authority", "--no-send", path}, nil, &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d, stderr=%s", code, stderr.String())
+	}
+}
+
+func TestRun_RequestTargetAndProxyAreMutuallyExclusive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--request-target", "origin", "--proxy", "http://proxy.local:8080", path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d, stderr=%s", code, stderr.String())
+	}
+}
+
+func TestRun_InvalidRequestTargetIsRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--request-target", "banana", path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d, stderr=%s", code, stderr.String())
+	}
+}
@@ -0,0 +1,66 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// resolveList collects repeated "host:port:addr" --resolve flag
+// occurrences and turns them into the dial-address overrides
+// client.Options.Resolve expects.
+type resolveList []string
+
+func (l *resolveList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *resolveList) Set(s string) error {
+	if _, _, err := parseResolveEntry(s); err != nil {
+		return err
+	}
+	*l = append(*l, s)
+	return nil
+}
+
+// toMap turns every collected "host:port:addr" entry into a map from
+// the dial address net/http actually asks for ("host:port") to the
+// address to dial instead ("addr:port"), so the Host header and TLS
+// SNI/certificate verification—both derived from the original
+// host:port—are left untouched.
+func (l resolveList) toMap() (map[string]string, error) {
+	if len(l) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]string, len(l))
+	for _, s := range l {
+		hostPort, addr, err := parseResolveEntry(s)
+		if err != nil {
+			return nil, err
+		}
+		m[hostPort] = addr
+	}
+	return m, nil
+}
+
+// parseResolveEntry splits a "host:port:addr" --resolve value into the
+// original dial address and its override. host and addr may each be a
+// bracketed IPv6 literal ("[::1]", "[fe80::1%eth0]"); host must be
+// bracketed to disambiguate its colons from the host:port:addr
+// separators, while addr--the last field--doesn't need brackets but is
+// accepted anyway for symmetry.
+func parseResolveEntry(s string) (hostPort, dialAddr string, err error) {
+	host, rest, splitErr := splitBracketedHost(s)
+	if splitErr != nil {
+		return "", "", fmt.Errorf("invalid --resolve value %q, expected host:port:addr", s)
+	}
+	port, addr, ok := strings.Cut(rest, ":")
+	if !ok || port == "" || addr == "" {
+		return "", "", fmt.Errorf("invalid --resolve value %q, expected host:port:addr", s)
+	}
+	addr = trimBrackets(addr)
+	return net.JoinHostPort(host, port), net.JoinHostPort(addr, port), nil
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/adamijak/http/internal/client"
+	"github.com/adamijak/http/internal/models"
+)
+
+func newTestRequest(t *testing.T, rawURL string) *models.Request {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := models.New()
+	req.Method = http.MethodGet
+	req.URL = u
+	return req
+}
+
+func TestResolveList_ToMap(t *testing.T) {
+	var l resolveList
+	if err := l.Set("example.com:443:10.0.0.5"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := l.toMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m["example.com:443"]; got != "10.0.0.5:443" {
+		t.Errorf("expected the override to preserve the original port, got %q", got)
+	}
+}
+
+func TestResolveList_SetRejectsMalformedEntry(t *testing.T) {
+	var l resolveList
+	if err := l.Set("example.com"); err == nil {
+		t.Fatal("expected an error for a malformed --resolve value")
+	}
+}
+
+func TestResolveList_ToMap_IPv6Literals(t *testing.T) {
+	var l resolveList
+	if err := l.Set("[::1]:443:[fe80::1%eth0]"); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := l.toMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := m["[::1]:443"]; got != "[fe80::1%eth0]:443" {
+		t.Errorf("expected a bracketed IPv6 override preserving the original port, got %q", got)
+	}
+}
+
+func TestResolveList_SetRejectsUnterminatedBracket(t *testing.T) {
+	var l resolveList
+	if err := l.Set("[::1:443:10.0.0.5"); err == nil {
+		t.Fatal("expected an error for an unterminated \"[\"")
+	}
+}
+
+// TestResolveList_DialsIPv6LoopbackOverride exercises the full path from
+// a "[host]:port:[addr]" --resolve flag value through to an actual
+// dial, against a server bound to the IPv6 loopback address, to prove
+// the bracketed form isn't just parsed but also dialable.
+func TestResolveList_DialsIPv6LoopbackOverride(t *testing.T) {
+	ln, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 loopback unavailable: %v", err)
+	}
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	srv.Listener = ln
+	srv.Start()
+	defer srv.Close()
+
+	_, port, err := net.SplitHostPort(ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var l resolveList
+	if err := l.Set(fmt.Sprintf("fake.example.internal:%s:[::1]", port)); err != nil {
+		t.Fatal(err)
+	}
+	m, err := l.toMap()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := newTestRequest(t, "http://fake.example.internal:"+port+"/")
+	c := client.New(client.Options{Resolve: m})
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected the request to reach the IPv6 loopback server, got body %q", resp.Body)
+	}
+}
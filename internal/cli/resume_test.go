@@ -0,0 +1,140 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// rangeCapableHandler serves body from a byte offset when sent a
+// "Range: bytes=N-" header, replying 206 Partial Content; otherwise it
+// serves the whole body with 200.
+func rangeCapableHandler(body []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rangeHeader := r.Header.Get("Range")
+		if rangeHeader == "" {
+			w.Write(body)
+			return
+		}
+		offsetStr := strings.TrimSuffix(strings.TrimPrefix(rangeHeader, "bytes="), "-")
+		offset, err := strconv.Atoi(offsetStr)
+		if err != nil || offset < 0 || offset > len(body) {
+			http.Error(w, "bad range", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Range", "bytes "+offsetStr+"-/"+strconv.Itoa(len(body)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write(body[offset:])
+	}
+}
+
+func TestRun_ResumeAppendsRemainderAfterAnInterruptedDownload(t *testing.T) {
+	full := []byte("0123456789ABCDEFGHIJ")
+	srv := httptest.NewServer(rangeCapableHandler(full))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(dir, "download.bin")
+
+	// Simulate a partial download that was interrupted partway through.
+	if err := os.WriteFile(outPath, full[:10], 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--output", outPath, "--resume", reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(full) {
+		t.Errorf("resumed file = %q, want %q", got, full)
+	}
+}
+
+func TestRun_ResumeRestartsWhenServerIgnoresRange(t *testing.T) {
+	full := []byte("hello world")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Ignores any Range header and always serves the full body with 200.
+		w.Write(full)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(dir, "download.bin")
+	if err := os.WriteFile(outPath, []byte("stale partial data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--output", outPath, "--resume", reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(full) {
+		t.Errorf("restarted file = %q, want %q", got, full)
+	}
+}
+
+func TestRun_OutputWritesBodyToFileWithoutResume(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("plain body"))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(dir, "out.txt")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--output", outPath, reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	got, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "plain body" {
+		t.Errorf("got %q, want %q", got, "plain body")
+	}
+}
+
+func TestRun_ResumeWithoutOutputErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--resume", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --resume without --output")
+	}
+}
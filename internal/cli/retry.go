@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/adamijak/http/internal/client"
+	"github.com/adamijak/http/internal/models"
+)
+
+// defaultRetryStatuses are the response statuses --retry retries against
+// when --retry-on-status isn't given: 429 (Too Many Requests) and 503
+// (Service Unavailable), the two most common "back off and try again"
+// signals a well-behaved client is expected to handle on its own.
+var defaultRetryStatuses = map[int]bool{429: true, 503: true}
+
+// parseRetryStatuses parses --retry-on-status's comma-separated status
+// code list into a lookup set. An empty value isn't an error; it's the
+// caller's job to fall back to defaultRetryStatuses in that case.
+func parseRetryStatuses(value string) (map[int]bool, error) {
+	if value == "" {
+		return nil, nil
+	}
+	statuses := make(map[int]bool)
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		code, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status %q: %w", part, err)
+		}
+		statuses[code] = true
+	}
+	return statuses, nil
+}
+
+// sendWithRetry sends req with c, retrying up to maxRetries additional
+// times (with delay between attempts) whenever the response's status is
+// in retryStatuses. A status outside retryStatuses, or a transport-level
+// error, is returned from the current attempt immediately; autoScheme is
+// applied on every attempt via sendWithAutoScheme.
+func sendWithRetry(c *client.Client, req *models.Request, autoScheme bool, maxRetries int, delay time.Duration, retryStatuses map[int]bool) (*models.HTTPResponse, error) {
+	resp, err := sendWithAutoScheme(c, req, autoScheme)
+	for attempt := 0; attempt < maxRetries && err == nil && retryStatuses[resp.StatusCode]; attempt++ {
+		time.Sleep(delay)
+		resp, err = sendWithAutoScheme(c, req, autoScheme)
+	}
+	return resp, err
+}
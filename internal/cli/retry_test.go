@@ -0,0 +1,104 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRetryStatuses_ParsesACommaSeparatedList(t *testing.T) {
+	statuses, err := parseRetryStatuses("429,502,503,504")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []int{429, 502, 503, 504} {
+		if !statuses[code] {
+			t.Errorf("expected %d to be retryable", code)
+		}
+	}
+	if statuses[500] {
+		t.Error("expected 500 not to be retryable")
+	}
+}
+
+func TestParseRetryStatuses_EmptyValueReturnsNil(t *testing.T) {
+	statuses, err := parseRetryStatuses("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if statuses != nil {
+		t.Errorf("expected nil for an empty value, got %v", statuses)
+	}
+}
+
+func TestParseRetryStatuses_RejectsANonNumericEntry(t *testing.T) {
+	if _, err := parseRetryStatuses("429,not-a-number"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestRun_RetryOnStatusRetriesUntilItSucceeds(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--retry", "5", "--retry-delay", "1ms", "--retry-on-status", "502", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if requests != 3 {
+		t.Errorf("expected 3 requests (2 failures + 1 success), got %d", requests)
+	}
+}
+
+func TestRun_ResponseOutsideRetryOnStatusReturnsImmediately(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--retry", "5", "--retry-delay", "1ms", "--retry-on-status", "503", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if requests != 1 {
+		t.Errorf("expected exactly 1 request since 502 isn't in --retry-on-status, got %d", requests)
+	}
+}
+
+func TestRun_InvalidRetryOnStatusIsRejected(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--retry-on-status", "oops", path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d, stderr=%s", code, stderr.String())
+	}
+}
@@ -0,0 +1,29 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/adamijak/http/internal/httpfile"
+	"github.com/adamijak/http/internal/models"
+)
+
+// saveRequest writes req's processed form (after prepareRequest has
+// resolved variables and injected headers, but before signing, which
+// --no-send also runs without) to path in .http format via
+// httpfile.Write, the same round-trip format --repl's save command
+// uses. --save doesn't imply --no-send: combine the two to snapshot a
+// request without sending it, or use --save alone to keep a copy of
+// what was actually sent. With multiple requests in one file, each
+// send overwrites path with that request, same as --output.
+func saveRequest(path string, req *models.Request) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("--save: %w", err)
+	}
+	defer f.Close()
+	if err := httpfile.Write(f, req); err != nil {
+		return fmt.Errorf("--save: %w", err)
+	}
+	return nil
+}
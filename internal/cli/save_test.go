@@ -0,0 +1,103 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_SaveWritesProcessedRequestToFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	savePath := filepath.Join(dir, "saved.http")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--save", savePath, path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	saved, err := os.ReadFile(savePath)
+	if err != nil {
+		t.Fatalf("expected --save to write %s: %v", savePath, err)
+	}
+	if !bytes.Contains(saved, []byte("GET "+srv.URL+"/ HTTP/1.1")) {
+		t.Errorf("expected the saved file to contain the processed request line, got %q", string(saved))
+	}
+	if !bytes.Contains(saved, []byte("Host: ")) {
+		t.Errorf("expected the saved file to contain the auto-added Host header, got %q", string(saved))
+	}
+}
+
+func TestRun_SaveThenRerunSendsIdenticalRequest(t *testing.T) {
+	var first, second string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		line := r.Method + " " + r.Header.Get("X-Custom")
+		if first == "" {
+			first = line
+		} else {
+			second = line
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\nX-Custom: abc\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	savePath := filepath.Join(dir, "saved.http")
+
+	var stdout, stderr bytes.Buffer
+	if code := Run([]string{"--save", savePath, path}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0 for the first run, got %d, stderr=%s", code, stderr.String())
+	}
+	if code := Run([]string{savePath}, nil, &stdout, &stderr); code != 0 {
+		t.Fatalf("expected exit 0 re-running the saved file, got %d, stderr=%s", code, stderr.String())
+	}
+
+	if first != second {
+		t.Errorf("expected the saved file to reproduce the original request, got %q and %q", first, second)
+	}
+}
+
+func TestRun_SaveWithNoSendSavesWithoutSending(t *testing.T) {
+	sent := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	savePath := filepath.Join(dir, "saved.http")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--save", savePath, "--no-send", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if sent {
+		t.Error("expected --no-send to keep --save from also sending the request")
+	}
+	if _, err := os.Stat(savePath); err != nil {
+		t.Errorf("expected --save to still write the file under --no-send: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_ForceHTTPOverridesScheme(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	// The file declares https, but the server only speaks plain HTTP;
+	// --http must rewrite the scheme before the request is sent.
+	httpsURL := "https://" + strings.TrimPrefix(srv.URL, "http://")
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+httpsURL+"\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--http", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "200 OK") {
+		t.Errorf("expected 200 OK in output, got %q", stdout.String())
+	}
+}
+
+func TestRun_ConflictingSchemeFlagsIsAnError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://example.com\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--http", "--https", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected non-zero exit for conflicting scheme flags")
+	}
+}
@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_SeedProducesByteIdenticalNoSendOutput(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "get.http")
+	body := "POST https://example.com/widgets\nContent-Type: application/json\n\n{\"id\": \"{{uuid}}\", \"n\": {{randInt}}}\n"
+	if err := os.WriteFile(path, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var first, second bytes.Buffer
+	var stderr bytes.Buffer
+	if code := Run([]string{"--seed", "42", "--no-send", path}, nil, &first, &stderr); code != 0 {
+		t.Fatalf("first run: expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if code := Run([]string{"--seed", "42", "--no-send", path}, nil, &second, &stderr); code != 0 {
+		t.Fatalf("second run: expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	if first.String() != second.String() {
+		t.Errorf("expected byte-identical output for the same seed, got:\n%q\nvs\n%q", first.String(), second.String())
+	}
+}
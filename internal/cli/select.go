@@ -0,0 +1,54 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// requestLabel returns r's display name, falling back to a placeholder
+// for the common case of an unnamed "###" block.
+func requestLabel(r *models.Request) string {
+	if r.Name == "" {
+		return "(unnamed)"
+	}
+	return r.Name
+}
+
+// fileRequestHeader returns r's display name qualified with the base
+// name of the file it came from, e.g. "auth.http: login", for the
+// per-request header printed before each response when a run spans
+// multiple files.
+func fileRequestHeader(r *models.Request) string {
+	if r.SourceFile == "" {
+		return requestLabel(r)
+	}
+	return filepath.Base(r.SourceFile) + ": " + requestLabel(r)
+}
+
+// selectRequest picks the single request in requests matching selector,
+// which is either a 1-based index or a request name matched
+// case-insensitively against the text following "###".
+func selectRequest(requests []*models.Request, selector string) (*models.Request, error) {
+	if i, err := strconv.Atoi(selector); err == nil {
+		if i < 1 || i > len(requests) {
+			return nil, fmt.Errorf("http: --select index %d out of range (file has %d requests)", i, len(requests))
+		}
+		return requests[i-1], nil
+	}
+
+	for _, r := range requests {
+		if strings.EqualFold(r.Name, selector) {
+			return r, nil
+		}
+	}
+
+	names := make([]string, len(requests))
+	for i, r := range requests {
+		names[i] = requestLabel(r)
+	}
+	return nil, fmt.Errorf("http: no request matching --select %q; available: %s", selector, strings.Join(names, ", "))
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func multiRequestFile(t *testing.T, srvURL string) string {
+	t.Helper()
+	content := "### first\n" +
+		"GET " + srvURL + "/one\n\n" +
+		"### Second\n" +
+		"GET " + srvURL + "/two\n\n"
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun_ListPrintsRequestNamesWithoutSending(t *testing.T) {
+	sent := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+	}))
+	defer srv.Close()
+
+	path := multiRequestFile(t, srv.URL)
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--list", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if sent {
+		t.Error("expected --list not to send any request")
+	}
+	want := "1: first\n2: Second\n"
+	if stdout.String() != want {
+		t.Errorf("expected %q, got %q", want, stdout.String())
+	}
+}
+
+func TestRun_SelectByNameIsCaseInsensitive(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+	}))
+	defer srv.Close()
+
+	path := multiRequestFile(t, srv.URL)
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--select", "second", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotPath != "/two" {
+		t.Errorf("expected the server to receive /two, got %q", gotPath)
+	}
+}
+
+func TestRun_SelectByIndex(t *testing.T) {
+	var gotPath string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+	}))
+	defer srv.Close()
+
+	path := multiRequestFile(t, srv.URL)
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--select", "1", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotPath != "/one" {
+		t.Errorf("expected the server to receive /one, got %q", gotPath)
+	}
+}
+
+func TestRun_SelectNoMatchListsAvailableNames(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	path := multiRequestFile(t, srv.URL)
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--select", "missing", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected non-zero exit for an unmatched --select")
+	}
+	if !strings.Contains(stderr.String(), "first") || !strings.Contains(stderr.String(), "Second") {
+		t.Errorf("expected available names in error, got %q", stderr.String())
+	}
+}
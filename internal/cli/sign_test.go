@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_SignAWSSigV4SetsAuthorizationHeader(t *testing.T) {
+	var gotAuth, gotDate string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotDate = r.Header.Get("X-Amz-Date")
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY")
+	t.Setenv("AWS_SESSION_TOKEN", "")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--sign", "aws-sigv4", "--aws-region", "us-east-1", "--aws-service", "execute-api", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.HasPrefix(gotAuth, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/") {
+		t.Errorf("unexpected Authorization header: %q", gotAuth)
+	}
+	if gotDate == "" {
+		t.Error("expected an X-Amz-Date header to be sent")
+	}
+}
+
+func TestRun_SignAWSSigV4RequiresRegionAndService(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--sign", "aws-sigv4", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for --sign aws-sigv4 without --aws-region/--aws-service")
+	}
+}
+
+func TestRun_SignAWSSigV4RequiresCredentialsInEnvironment(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--sign", "aws-sigv4", "--aws-region", "us-east-1", "--aws-service", "execute-api", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit without AWS credentials in the environment")
+	}
+}
+
+func TestRun_SignInvalidSchemeErrors(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--sign", "hmac-legacy", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatal("expected a non-zero exit for an unknown --sign scheme")
+	}
+}
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRun_SortHeadersAlignsColonsInAColumn(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Age", "2")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	path := writeJSONRequestFile(t, srv.URL)
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--sort-headers", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Age           : 2\n") || !strings.Contains(stdout.String(), "Content-Type  : application/json\n") {
+		t.Errorf("expected aligned header colons, got %q", stdout.String())
+	}
+}
+
+func TestRun_WithoutSortHeadersLeavesColumnsUnaligned(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Age", "2")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	defer srv.Close()
+
+	path := writeJSONRequestFile(t, srv.URL)
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "Age: 2\n") {
+		t.Errorf("expected unaligned headers by default, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRun_StreamFlushesBodyToStdout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		w.Write([]byte("data: one\n\n"))
+		flusher.Flush()
+		w.Write([]byte("data: two\n\n"))
+		flusher.Flush()
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--stream", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	out := stdout.String()
+	if !bytes.Contains([]byte(out), []byte("data: one\n\n")) || !bytes.Contains([]byte(out), []byte("data: two\n\n")) {
+		t.Errorf("expected both SSE events in stdout, got %q", out)
+	}
+	if !bytes.Contains([]byte(out), []byte("200 OK")) {
+		t.Errorf("expected the status line in stdout, got %q", out)
+	}
+}
@@ -0,0 +1,19 @@
+package cli
+
+import "strings"
+
+// stringList collects repeated occurrences of a flag into a slice, such
+// as --skip-check.
+type stringList []string
+
+func (l *stringList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
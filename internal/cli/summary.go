@@ -0,0 +1,23 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// writeSummary writes a single machine-readable line describing resp for
+// --summary, letting a CI script grep key facts (status, size, timing,
+// redirect count, host) without parsing JSON or the human-readable
+// response output. The format is stable: space-separated key=value
+// pairs, always in this order.
+func writeSummary(w io.Writer, req *models.Request, resp *models.HTTPResponse) {
+	host := req.URL.Hostname()
+	if final, err := url.Parse(resp.FinalURL); err == nil && final.Hostname() != "" {
+		host = final.Hostname()
+	}
+	fmt.Fprintf(w, "status=%d bytes=%d time=%.3fs redirects=%d host=%s\n",
+		resp.StatusCode, len(resp.RawBody), resp.Timing.Seconds(), resp.Redirects, host)
+}
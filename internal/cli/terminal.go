@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"io"
+	"os"
+)
+
+// isTerminal reports whether w is a character device such as an
+// interactive terminal, for --color auto. It's a best-effort check:
+// anything that isn't a *os.File (a bytes.Buffer in tests, a pipe
+// wrapped in a different type) is treated as non-interactive.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
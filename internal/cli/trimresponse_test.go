@@ -0,0 +1,100 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_TrimResponseLimitsPrintedBodyButNotOutputFile(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "0123456789")
+	}))
+	defer srv.Close()
+
+	reqPath := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outPath := filepath.Join(t.TempDir(), "out.bin")
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-only", "--trim-response", "4", "--output", outPath, reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if stdout.String() != "" {
+		t.Errorf("expected --output to suppress printing, got stdout %q", stdout.String())
+	}
+
+	saved, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(saved) != "0123456789" {
+		t.Errorf("expected --output to save the full body, got %q", saved)
+	}
+}
+
+func TestRun_TrimResponsePrintsTruncatedBodyWithMarker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "0123456789")
+	}))
+	defer srv.Close()
+
+	reqPath := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-only", "--trim-response", "4", reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if got := stdout.String(); got != "0123[... 6 more bytes]\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRun_TrimLinesPrintsTruncatedBodyWithMarker(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "one\ntwo\nthree\n")
+	}))
+	defer srv.Close()
+
+	reqPath := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--body-only", "--trim-lines", "1", reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if got := stdout.String(); !strings.HasPrefix(got, "one\n[... 2 more lines]") {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRun_TrimResponseAndTrimLinesAreMutuallyExclusive(t *testing.T) {
+	reqPath := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(reqPath, []byte("GET http://example.com/\nHost: example.com\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--trim-response", "10", "--trim-lines", "5", reqPath}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "mutually exclusive") {
+		t.Errorf("expected a mutually-exclusive error, got %q", stderr.String())
+	}
+}
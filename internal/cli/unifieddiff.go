@@ -0,0 +1,100 @@
+package cli
+
+import "fmt"
+
+// unifiedDiff renders the differences between before and after in a
+// unified-diff style ("---"/"+++" file headers, then every line prefixed
+// "  ", "- " or "+ "), the format --baseline uses to show a response
+// regression. It writes no "@@" hunk headers and no context trimming:
+// every line of both sides appears once. It returns "" when before and
+// after are equal.
+func unifiedDiff(beforeLabel, afterLabel string, before, after []string) string {
+	ops := diffLines(before, after)
+	if allEqual(ops) {
+		return ""
+	}
+
+	var out string
+	out += fmt.Sprintf("--- %s\n", beforeLabel)
+	out += fmt.Sprintf("+++ %s\n", afterLabel)
+	for _, op := range ops {
+		switch op.kind {
+		case diffEqual:
+			out += fmt.Sprintf("  %s\n", op.line)
+		case diffDelete:
+			out += fmt.Sprintf("- %s\n", op.line)
+		case diffInsert:
+			out += fmt.Sprintf("+ %s\n", op.line)
+		}
+	}
+	return out
+}
+
+type diffKind int
+
+const (
+	diffEqual diffKind = iota
+	diffDelete
+	diffInsert
+)
+
+type diffOp struct {
+	kind diffKind
+	line string
+}
+
+func allEqual(ops []diffOp) bool {
+	for _, op := range ops {
+		if op.kind != diffEqual {
+			return false
+		}
+	}
+	return true
+}
+
+// diffLines finds the longest common subsequence of before and after via
+// the standard O(n*m) dynamic-programming table, then walks it backward
+// to produce a line-by-line edit script. Response files are small enough
+// (headers plus a body) that the quadratic cost doesn't matter here.
+func diffLines(before, after []string) []diffOp {
+	n, m := len(before), len(after)
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var ops []diffOp
+	i, j := 0, 0
+	for i < n && j < m {
+		switch {
+		case before[i] == after[j]:
+			ops = append(ops, diffOp{diffEqual, before[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, diffOp{diffDelete, before[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{diffInsert, after[j]})
+			j++
+		}
+	}
+	for ; i < n; i++ {
+		ops = append(ops, diffOp{diffDelete, before[i]})
+	}
+	for ; j < m; j++ {
+		ops = append(ops, diffOp{diffInsert, after[j]})
+	}
+	return ops
+}
@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestRun_UploadFileStreamsFileContentsAsBody exercises --upload-file
+// end to end: a file's contents should reach the server as the request
+// body, with Content-Length set from the file's size.
+func TestRun_UploadFileStreamsFileContentsAsBody(t *testing.T) {
+	body := strings.Repeat("x", 5*32*1024) // well over a 32KB buffer
+
+	var gotBody string
+	var gotContentLength int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotContentLength = r.ContentLength
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("reading uploaded body: %v", err)
+		}
+		gotBody = string(data)
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	uploadPath := filepath.Join(dir, "payload.bin")
+	if err := os.WriteFile(uploadPath, []byte(body), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte(fmt.Sprintf("PUT %s/\n\n", srv.URL)), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--upload-file", uploadPath, reqPath}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotBody != body {
+		t.Errorf("expected the uploaded file's contents as the body, got %d bytes", len(gotBody))
+	}
+	if gotContentLength != int64(len(body)) {
+		t.Errorf("expected Content-Length %d, got %d", len(body), gotContentLength)
+	}
+}
+
+func TestRun_UploadFileAndBodyStdinAreMutuallyExclusive(t *testing.T) {
+	dir := t.TempDir()
+	uploadPath := filepath.Join(dir, "payload.bin")
+	if err := os.WriteFile(uploadPath, []byte("data"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reqPath := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(reqPath, []byte("PUT http://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--upload-file", uploadPath, "--body-stdin", reqPath}, strings.NewReader("x"), &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "mutually exclusive") {
+		t.Errorf("expected an error mentioning mutual exclusivity, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,29 @@
+package cli
+
+import "github.com/adamijak/http/internal/models"
+
+// defaultUserAgent is sent when a request has no User-Agent header of
+// its own and --no-default-headers wasn't given. The CLI doesn't track
+// a release version yet (see internal/export/har.go's creatorVersion),
+// so "dev" is a placeholder here too.
+const defaultUserAgent = "adamijak-http/dev"
+
+// applyUserAgent sets req's User-Agent header. --user-agent always wins
+// when given; a file that already set one is left alone; otherwise a
+// request gets defaultUserAgent, unless --no-default-headers suppresses
+// it. Suppressing it sets an explicit blank User-Agent rather than
+// leaving the header unset, since net/http otherwise fills in its own
+// "Go-http-client/1.1" default for any request with no User-Agent at
+// all.
+func applyUserAgent(req *models.Request, userAgent string, noDefaultHeaders bool) {
+	switch {
+	case userAgent != "":
+		req.SetHeader("User-Agent", userAgent)
+	case req.Header.Get("User-Agent") != "":
+		return
+	case noDefaultHeaders:
+		req.SetHeader("User-Agent", "")
+	default:
+		req.SetHeader("User-Agent", defaultUserAgent)
+	}
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_URLUserinfoDialsTheHostAndSendsAnAuthorizationHeader(t *testing.T) {
+	var gotAuth, gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		gotHost = r.Host
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	host := strings.TrimPrefix(srv.URL, "http://")
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET http://alice:hunter2@"+host+"/widgets\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+	if gotHost != host {
+		t.Errorf("expected the server to see Host %q (no userinfo), got %q", host, gotHost)
+	}
+	if gotAuth != "Basic YWxpY2U6aHVudGVyMg==" {
+		t.Errorf("expected the userinfo to arrive as a Basic Authorization header, got %q", gotAuth)
+	}
+	if !strings.Contains(stderr.String(), "Authorization header") {
+		t.Errorf("expected a userinfo warning, got %s", stderr.String())
+	}
+}
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun_ValidateOnlyExitsZeroForACleanRequest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\nHost: example.com\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--validate-only", path}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stdout=%s stderr=%s", code, stdout.String(), stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "ok") {
+		t.Errorf("expected a clean report on stdout, got %q", stdout.String())
+	}
+	if stdout.String() == "" {
+		t.Error("expected --validate-only to print a report, not silence")
+	}
+}
+
+func TestRun_ValidateOnlyExitsNonZeroForAnInvalidRequestAndPrintsReport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	// The asterisk-form target requires an explicit Host header to know
+	// where to send it; Validate rejects it outright without one.
+	if err := os.WriteFile(path, []byte("OPTIONS * HTTP/1.1\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--validate-only", path}, nil, &stdout, &stderr)
+	if code == 0 {
+		t.Fatalf("expected a non-zero exit for an invalid request, stdout=%s", stdout.String())
+	}
+	if !strings.Contains(stdout.String(), "error:") {
+		t.Errorf("expected the validation report to include the error, got %q", stdout.String())
+	}
+}
+
+func TestRun_ValidateOnlyAndNoSendAreMutuallyExclusive(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "req.http")
+	if err := os.WriteFile(path, []byte("GET https://example.com/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--validate-only", "--no-send", path}, nil, &stdout, &stderr)
+	if code != 2 {
+		t.Fatalf("expected exit 2, got %d", code)
+	}
+	if !strings.Contains(stderr.String(), "mutually exclusive") {
+		t.Errorf("expected an error mentioning mutual exclusivity, got %q", stderr.String())
+	}
+}
@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/adamijak/http/internal/chain"
+	"github.com/adamijak/http/internal/client"
+	"github.com/adamijak/http/internal/models"
+	"github.com/adamijak/http/internal/validate"
+)
+
+// watchPollInterval is how often runWatch checks path's mtime for a
+// change. Polling, rather than a filesystem-notification API, keeps
+// this dependency-free and works the same across platforms.
+const watchPollInterval = 200 * time.Millisecond
+
+// clearScreen is written before each re-run so a --watch session shows
+// only the latest response instead of every one it has ever printed.
+const clearScreen = "\033[H\033[2J"
+
+// watchOptions bundles the request-processing settings runWatch needs
+// to reparse and resend path on every change, mirroring the flags
+// prepareRequest and client.New already take in the non-watch path.
+type watchOptions struct {
+	maxHeaderLines   int
+	format           string
+	query            keyValueList
+	forceHTTP        bool
+	forceHTTPS       bool
+	head             bool
+	discover         bool
+	noBody           bool
+	form             formInput
+	vopts            validate.ValidateOptions
+	compress         string
+	userAgent        string
+	requestTarget    string
+	noDefaultHeaders bool
+	headerCase       string
+	clientOpts       client.Options
+}
+
+// runWatch polls path's modification time and, on each change,
+// re-parses and re-sends every request it contains, until stop is
+// closed. A parse, validation, or send error is printed and watching
+// continues rather than exiting, since the whole point is riding out a
+// developer's in-progress edits to the file.
+func runWatch(path string, opts watchOptions, stdout, stderr io.Writer, stop <-chan struct{}) {
+	var lastMod time.Time
+	for {
+		info, err := os.Stat(path)
+		switch {
+		case err != nil:
+			fmt.Fprintln(stderr, err)
+		case info.ModTime().After(lastMod):
+			lastMod = info.ModTime()
+			fmt.Fprint(stdout, clearScreen)
+			watchRunOnce(path, opts, stdout, stderr)
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(watchPollInterval):
+		}
+	}
+}
+
+// watchRunOnce parses path and sends every request it contains,
+// printing each response in turn. It never returns an error: problems
+// are printed to stderr so the caller keeps watching instead of
+// exiting.
+func watchRunOnce(path string, opts watchOptions, stdout, stderr io.Writer) {
+	requests, warnings, err := parseFile(path, opts.maxHeaderLines, opts.format)
+	for _, w := range warnings {
+		fmt.Fprintf(stderr, "warning: %s: %s\n", path, w)
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return
+	}
+
+	store := make(chain.Store)
+	for _, req := range requests {
+		// --watch re-runs unattended on every file change, so --prompt's
+		// interactive fill-in doesn't apply here; an undefined variable
+		// just fails this pass like any other error, per watchRunOnce's
+		// doc comment above.
+		reqWarnings, err := prepareRequest(req, store, opts.query, opts.forceHTTP, opts.forceHTTPS, opts.head, opts.discover, opts.noBody, nil, nil, opts.form, opts.vopts, opts.compress, opts.userAgent, opts.requestTarget, opts.noDefaultHeaders, false, nil, stderr)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			continue
+		}
+		for _, w := range reqWarnings {
+			fmt.Fprintf(stderr, "warning: %s\n", w)
+		}
+
+		clientOpts := opts.clientOpts
+		clientOpts.PreferHTTP2 = req.IsHTTP2()
+		resp, err := client.New(clientOpts).Do(req)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			continue
+		}
+		resp.Print(stdout, models.PrintOptions{})
+	}
+}
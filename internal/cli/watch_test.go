@@ -0,0 +1,117 @@
+package cli
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/adamijak/http/internal/client"
+)
+
+// syncBuffer is a concurrency-safe io.Writer, needed here because
+// runWatch's goroutine and the test's polling both touch stdout/stderr.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf []byte
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+func (b *syncBuffer) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.buf)
+}
+
+func TestRunWatch_ResendsOnFileModification(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	var stdout, stderr syncBuffer
+	done := make(chan struct{})
+	go func() {
+		runWatch(path, watchOptions{clientOpts: client.Options{}}, &stdout, &stderr, stop)
+		close(done)
+	}()
+
+	waitForCount(t, &count, 1)
+
+	// Modifying the file bumps its mtime, which runWatch polls for.
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	waitForCount(t, &count, 2)
+
+	close(stop)
+	<-done
+}
+
+func TestRunWatch_ParseErrorIsPrintedAndWatchingContinues(t *testing.T) {
+	var count int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&count, 1)
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("not a valid request line\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := make(chan struct{})
+	var stdout, stderr syncBuffer
+	done := make(chan struct{})
+	go func() {
+		runWatch(path, watchOptions{clientOpts: client.Options{}}, &stdout, &stderr, stop)
+		close(done)
+	}()
+
+	waitForCondition(t, func() bool { return stderr.Len() > 0 })
+
+	if err := os.WriteFile(path, []byte("GET "+srv.URL+"/\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	waitForCount(t, &count, 1)
+
+	close(stop)
+	<-done
+}
+
+func waitForCount(t *testing.T, count *int32, want int32) {
+	t.Helper()
+	waitForCondition(t, func() bool { return atomic.LoadInt32(count) >= want })
+}
+
+func waitForCondition(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if condition() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("timed out waiting for condition")
+}
@@ -0,0 +1,167 @@
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/adamijak/http/internal/wsframe"
+)
+
+// startEchoWebSocketServer performs the RFC 6455 handshake on each
+// accepted connection and echoes back every text frame it receives
+// until the client sends a Close frame.
+func startEchoWebSocketServer(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		br := bufio.NewReader(conn)
+		req, err := http.ReadRequest(br)
+		if err != nil {
+			return
+		}
+		accept := websocketAcceptKey(req.Header.Get("Sec-WebSocket-Key"))
+		resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\n" +
+			"Connection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + accept + "\r\n\r\n"
+		if _, err := conn.Write([]byte(resp)); err != nil {
+			return
+		}
+
+		for {
+			frame, err := wsframe.Read(br)
+			if err != nil {
+				return
+			}
+			switch frame.Opcode {
+			case wsframe.OpClose:
+				wsframe.WriteUnmasked(conn, wsframe.Frame{Opcode: wsframe.OpClose})
+				return
+			case wsframe.OpText, wsframe.OpBinary:
+				if err := wsframe.WriteUnmasked(conn, frame); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestRun_WebSocketEchoesLinesSentOnStdin(t *testing.T) {
+	addr := startEchoWebSocketServer(t)
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET ws://"+addr+"/chat\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	stdin := strings.NewReader("hello\nworld\n")
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--websocket", path}, stdin, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected exit 0, got %d, stderr=%s", code, stderr.String())
+	}
+
+	got := stdout.String()
+	if !strings.Contains(got, "hello") || !strings.Contains(got, "world") {
+		t.Errorf("expected the echoed frames in stdout, got %q", got)
+	}
+}
+
+func TestReadHandshakeResponse_AccumulatesDuplicateHeadersInsteadOfOverwriting(t *testing.T) {
+	raw := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: abc\r\n" +
+		"X-Trace: first\r\n" +
+		"X-Trace: second\r\n\r\n"
+	httpReq, err := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, _, err := readHandshakeResponse(strings.NewReader(raw), httpReq, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := resp.Header.Values("X-Trace")
+	want := []string{"first", "second"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("expected duplicate X-Trace headers to accumulate as %v, got %v", want, got)
+	}
+}
+
+func TestReadHandshakeResponse_RejectsHeadersExceedingTheCap(t *testing.T) {
+	var raw strings.Builder
+	raw.WriteString("HTTP/1.1 101 Switching Protocols\r\n")
+	for i := 0; i < 1000; i++ {
+		raw.WriteString("X-Padding: 0123456789012345678901234567890123456789\r\n")
+	}
+	raw.WriteString("\r\n")
+
+	httpReq, err := http.NewRequest(http.MethodGet, "http://example.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = readHandshakeResponse(strings.NewReader(raw.String()), httpReq, 512)
+	if err == nil {
+		t.Fatal("expected an error for headers exceeding the cap")
+	}
+	if !strings.Contains(err.Error(), "max-response-header-size") {
+		t.Errorf("expected the error to name --max-response-header-size, got %q", err)
+	}
+}
+
+func TestRun_WebSocketRejectsANon101Response(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		br := bufio.NewReader(conn)
+		if _, err := http.ReadRequest(br); err != nil {
+			return
+		}
+		conn.Write([]byte("HTTP/1.1 400 Bad Request\r\nContent-Length: 0\r\n\r\n"))
+	}()
+
+	path := filepath.Join(t.TempDir(), "req.http")
+	if err := os.WriteFile(path, []byte("GET ws://"+ln.Addr().String()+"/chat\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := Run([]string{"--websocket", path}, strings.NewReader(""), &stdout, &stderr)
+	if code != 1 {
+		t.Fatalf("expected exit 1, got %d, stderr=%s", code, stderr.String())
+	}
+	if !strings.Contains(stderr.String(), "refused the upgrade") {
+		t.Errorf("expected an upgrade-refused error, got %q", stderr.String())
+	}
+}
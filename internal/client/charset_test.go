@@ -0,0 +1,58 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestDo_DecodesLatin1BodyToUTF8 asserts a Content-Type charset of
+// ISO-8859-1 is decoded to UTF-8 for Body while RawBody keeps the
+// original bytes, so accented characters print correctly instead of as
+// mojibake.
+func TestDo_DecodesLatin1BodyToUTF8(t *testing.T) {
+	// "café" in ISO-8859-1: é is the single byte 0xE9.
+	raw := []byte{'c', 'a', 'f', 0xE9}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=ISO-8859-1")
+		w.Write(raw)
+	}))
+	defer srv.Close()
+
+	resp, err := New(Options{}).Do(newTestRequest(t, srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "café"; resp.Body != want {
+		t.Errorf("Body = %q, want %q", resp.Body, want)
+	}
+	if string(resp.RawBody) != string(raw) {
+		t.Errorf("RawBody = %v, want the original bytes %v", resp.RawBody, raw)
+	}
+	if resp.CharsetWarning != "" {
+		t.Errorf("expected no charset warning, got %q", resp.CharsetWarning)
+	}
+}
+
+// TestDo_UnsupportedCharsetFallsBackWithWarning asserts an unrecognized
+// or unsupported charset leaves Body as the raw bytes and reports a
+// warning instead of mangling the text.
+func TestDo_UnsupportedCharsetFallsBackWithWarning(t *testing.T) {
+	raw := []byte{0x82, 0xA0} // "あ" in Shift_JIS.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=Shift_JIS")
+		w.Write(raw)
+	}))
+	defer srv.Close()
+
+	resp, err := New(Options{}).Do(newTestRequest(t, srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != string(raw) {
+		t.Errorf("Body = %v, want the raw bytes unchanged %v", []byte(resp.Body), raw)
+	}
+	if resp.CharsetWarning == "" {
+		t.Error("expected a charset warning for an unsupported charset")
+	}
+}
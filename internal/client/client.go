@@ -0,0 +1,717 @@
+// Package client sends requests built by the request package and reads
+// back a Response.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/adamijak/http/internal/charset"
+	"github.com/adamijak/http/internal/digest"
+	"github.com/adamijak/http/internal/models"
+	"github.com/adamijak/http/internal/ntlm"
+)
+
+// IdempotentMethods holds the request methods safe to repeat without
+// side effects, per RFC 7231 semantics.
+var IdempotentMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodOptions: true,
+	http.MethodPut:     true,
+	http.MethodDelete:  true,
+	http.MethodTrace:   true,
+}
+
+// Options configures a Client. The zero value is the tool's default
+// behavior; fields are added here as new flags need to influence how
+// requests are sent, rather than growing New's parameter list.
+type Options struct {
+	// Proxy, when set, routes every request through this proxy URL. For
+	// an http:// target this makes the outgoing request line
+	// absolute-form (method absolute-URI proto), as required for
+	// proxy-style requests; for an https:// target net/http tunnels via
+	// CONNECT instead.
+	Proxy *url.URL
+
+	// ProxyCACert, when Proxy's scheme is https, is trusted for the
+	// proxy's own TLS certificate, independently of whatever verifies
+	// the origin server's (nil falls back to the system roots). It's
+	// ignored for a plain http:// Proxy, which has no TLS certificate of
+	// its own to verify.
+	ProxyCACert *x509.CertPool
+
+	// ProxyInsecureSkipVerify disables verification of the proxy's own
+	// TLS certificate, independently of the origin server's
+	// verification. Like ProxyCACert, it only applies to an https://
+	// Proxy.
+	ProxyInsecureSkipVerify bool
+
+	// RequestTargetForm selects the request-target written on the wire,
+	// per RFC 7230 §5.3: "origin" (the default; path+query, as sent
+	// directly to an origin server), "absolute" (a full absolute-URI, as
+	// sent to a proxy), "authority" (host:port, valid only for CONNECT),
+	// or "asterisk" (the literal "*", valid only for OPTIONS). "" behaves
+	// like "origin" except that a naturally CONNECT or OPTIONS-* request
+	// still gets its required authority/asterisk form automatically.
+	//
+	// "absolute" is implemented without a real Proxy by pointing the
+	// Transport's Proxy func at the request's own URL: net/http then
+	// treats the request as proxied (writing absolute-form) while still
+	// dialing the real origin, since "the proxy" and "the origin" are the
+	// same address. It's ignored when Proxy is already set, since that
+	// already produces absolute-form for an http:// target.
+	RequestTargetForm string
+
+	// MaxBodySize caps how many response body bytes are read, regardless
+	// of whether the response used Content-Length, chunked encoding, or
+	// was delimited by connection close. 0 means unlimited.
+	MaxBodySize int64
+
+	// MaxResponseHeaderBytes caps how many bytes of status line and
+	// header data net/http will buffer while reading a response, so a
+	// server that never terminates its status line (or sends an
+	// enormous run of headers) can't be used to grow memory without
+	// bound. 0 falls back to net/http's own built-in default (10MB).
+	MaxResponseHeaderBytes int64
+
+	// Trace, if non-nil, receives a curl -v style dump of the outgoing
+	// request, negotiated TLS details, and the raw response, all lines
+	// prefixed "> "/"* "/"< " respectively.
+	Trace io.Writer
+
+	// TraceShowSecrets disables redaction of Authorization and Cookie
+	// header values in the Trace output.
+	TraceShowSecrets bool
+
+	// Stream, if non-nil, receives the status line, headers, and body as
+	// they arrive: the status line and headers as soon as they're
+	// received, then each chunk read off the body as it's read, rather
+	// than the whole response being buffered before send returns.
+	// MaxBodySize is ignored while Stream is set, since the point is to
+	// keep up with a live SSE or chunked feed rather than cap its size.
+	// The full body is still accumulated and returned in
+	// HTTPResponse.Body/RawBody, so captures, --expect, and --output
+	// keep working the same as an unstreamed response.
+	Stream io.Writer
+
+	// PreferHTTP2 offers "h2" via ALPN for https requests and forces an
+	// HTTP/2 attempt. A server that doesn't negotiate h2 is served over
+	// HTTP/1.1 as usual; the caller can tell by checking the response's
+	// Proto.
+	PreferHTTP2 bool
+
+	// Timeout bounds the whole round trip: connect, write the request,
+	// and read the response. 0 falls back to a 30 second default.
+	Timeout time.Duration
+
+	// Context, when set, is the base every outgoing request's context is
+	// built from, instead of context.Background(). The CLI uses this to
+	// wire a SIGINT/SIGTERM-cancelable context through Do so Ctrl-C
+	// aborts an in-flight request (closing its connection) rather than
+	// leaving it to run to completion or killing the process mid-write.
+	Context context.Context
+
+	// TimeoutConnect, if positive, separately bounds just the
+	// DNS-resolve-and-dial phase, so a slow-to-connect host can be told
+	// apart from a host that connects fine but is slow to respond. It's
+	// still subject to Timeout as an outer bound; 0 leaves connect
+	// governed by Timeout alone.
+	TimeoutConnect time.Duration
+
+	// FailEarly does a quick DNS lookup of the dial address ahead of
+	// connecting, so an unresolvable host fails immediately with a clear
+	// "could not resolve host" error instead of only surfacing that as a
+	// generic dial error after whatever's left of the connect timeout.
+	FailEarly bool
+
+	// FailEarlyTimeout bounds the FailEarly lookup; 0 uses
+	// defaultFailEarlyTimeout. A lookup that merely times out (a slow or
+	// unreachable resolver, as opposed to a clear NXDOMAIN) isn't treated
+	// as unresolvable: dialing proceeds normally and reports whatever
+	// happens there instead.
+	FailEarlyTimeout time.Duration
+
+	// MaxTime, if positive, is a hard wall-clock deadline on the entire
+	// send+receive, enforced with a context that closes the connection
+	// when it expires. Unlike Timeout, which net/http itself resets on
+	// forward progress in some transport configurations, MaxTime can't
+	// be extended by a server that trickles bytes to stay alive; it
+	// always fires at MaxTime after the request starts.
+	MaxTime time.Duration
+
+	// Resolve overrides which address is actually dialed for a given
+	// "host:port", keyed and valued as such (e.g.
+	// "example.com:443" -> "10.0.0.5:443"). The Host header and TLS
+	// SNI/certificate verification are unaffected, since both are
+	// derived from the request's original host:port rather than from
+	// whatever address the connection ends up dialing.
+	Resolve map[string]string
+
+	// ConnectTo remaps a given "host:port" to a different
+	// "connecthost:connectport" to dial instead, keyed and valued as
+	// such (e.g. "api.example.com:443" -> "backend-7.internal:8443").
+	// Unlike Resolve, the replacement is itself resolved normally rather
+	// than dialed as a literal address, which is what makes it useful
+	// for pointing at a named backend instead of a bare IP. As with
+	// Resolve, the Host header and TLS SNI/certificate verification are
+	// unaffected, since both come from the request's original host:port.
+	// ConnectTo is applied before Resolve, so a connect-to target can
+	// still be overridden to a specific IP.
+	ConnectTo map[string]string
+
+	// HeaderCase controls the header name casing sent on the wire: "" or
+	// "preserve" (as written in the .http file), "canonical"
+	// (Content-Type), or "lower" (HTTP/2 style). See
+	// models.Request.WireHeaders.
+	HeaderCase string
+
+	// SNI overrides the server name sent in the TLS ClientHello, without
+	// changing the Host header or the request's target. Leaving it empty
+	// uses the URL's hostname, net/http's default. Since certificate
+	// verification checks the presented certificate against this name
+	// rather than the Host header, an override that doesn't match what
+	// the server actually presents will fail the handshake unless
+	// combined with skipping verification.
+	SNI string
+
+	// MaxRedirects caps how many redirects a request will follow before
+	// Do gives up and returns an error, same shape as curl's
+	// --max-redirs. 0 uses defaultMaxRedirects.
+	MaxRedirects int
+
+	// LocationTrusted forwards the original Authorization and Cookie
+	// headers across a redirect to a different host. Without it, both
+	// are dropped on a cross-host hop, matching curl and browser
+	// behavior, since net/http's default CheckRedirect already does
+	// this stripping and Do's own CheckRedirect only restores the
+	// headers when LocationTrusted asks for it.
+	LocationTrusted bool
+
+	// Pins, when non-empty, restricts TLS connections to servers whose
+	// certificate's SubjectPublicKeyInfo hashes to one of them, each
+	// formatted "sha256//<base64 of the SHA-256 digest>" per RFC 7469's
+	// pin-sha256 syntax. Any pin matching is enough to pass. This runs
+	// in addition to, not instead of, the normal certificate chain
+	// verification: a certificate that fails the usual checks is
+	// rejected before pinning is even considered.
+	Pins []string
+
+	// NTLM, when set, authenticates with NTLM instead of sending req
+	// directly. Unlike the signing schemes in internal/sign, NTLM can't
+	// be reduced to mutating a request once before it's sent: the server
+	// issues a random challenge that has to be echoed back in a second
+	// request, so the exchange happens inside Do itself. See doNTLM.
+	NTLM *NTLMCredentials
+
+	// Digest, when set, authenticates with HTTP Digest (RFC 7616)
+	// instead of sending req directly. Like NTLM, the server's nonce
+	// challenge has to be echoed back in a second request, so the
+	// exchange happens inside Do itself. See doDigest.
+	Digest *DigestCredentials
+
+	// DisallowedRedirectHosts, when non-empty, aborts the request the
+	// moment a redirect's Location points at one of these hostnames
+	// (case-insensitive, port ignored), for --no-redirect-to. It guards
+	// against an open-redirect regression or an accidental hop to a
+	// disallowed host (e.g. staging leaking traffic to prod) rather
+	// than silently following it.
+	DisallowedRedirectHosts []string
+}
+
+// NTLMCredentials identifies the account NTLM authenticates as.
+type NTLMCredentials struct {
+	Domain   string
+	User     string
+	Password string
+}
+
+// DigestCredentials identifies the account Digest authenticates as.
+type DigestCredentials struct {
+	User     string
+	Password string
+}
+
+// defaultMaxRedirects matches net/http's own built-in redirect cap, used
+// when Options.MaxRedirects is 0.
+const defaultMaxRedirects = 10
+
+// defaultExpectContinueTimeout is how long a request with an
+// "Expect: 100-continue" header waits for the server's 100 Continue
+// before sending its body anyway. net/http's Transport otherwise leaves
+// this at zero, which sends the body immediately and defeats the point
+// of the header (letting the server reject a large upload, e.g. with 401
+// or 417, before the client wastes bandwidth streaming it).
+const defaultExpectContinueTimeout = 1 * time.Second
+
+// Client sends requests over the network.
+type Client struct {
+	HTTPClient *http.Client
+	Options    Options
+
+	// redirectWarnings accumulates warnings noticed by checkRedirect
+	// across a single Do call's redirect chain (e.g. an https->http
+	// downgrade), since CheckRedirect has no other way to report
+	// something short of failing the request outright. New's caller
+	// creates one Client per request, so this needs no locking.
+	redirectWarnings []string
+
+	// redirectCount counts the hops checkRedirect has approved for the
+	// current Do call, for the same reason redirectWarnings exists:
+	// CheckRedirect is the only hook that observes each hop as it
+	// happens.
+	redirectCount int
+}
+
+// New returns a Client configured by opts.
+func New(opts Options) *Client {
+	transport := &http.Transport{MaxResponseHeaderBytes: opts.MaxResponseHeaderBytes, ExpectContinueTimeout: defaultExpectContinueTimeout}
+	proxyHandshakesTLSItself := opts.Proxy != nil && opts.Proxy.Scheme == "https" && (opts.ProxyCACert != nil || opts.ProxyInsecureSkipVerify)
+	if opts.Proxy != nil {
+		proxyURL := opts.Proxy
+		if proxyHandshakesTLSItself {
+			// net/http has no way to verify an https:// proxy's own
+			// certificate separately from the origin server's: it dials
+			// and TLS-handshakes an https-scheme proxy internally, ahead
+			// of the CONNECT tunnel, using the same TLSClientConfig it
+			// verifies the origin with. Disguising the proxy as
+			// http-scheme instead makes net/http dial it as a plain TCP
+			// connection (via DialContext, wired up below to do the real
+			// TLS handshake itself with a proxy-specific tls.Config),
+			// then run the usual CONNECT-and-tunnel logic over the
+			// result exactly as it would for a plaintext proxy.
+			disguised := *opts.Proxy
+			disguised.Scheme = "http"
+			proxyURL = &disguised
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	} else if opts.RequestTargetForm == "absolute" {
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return req.URL, nil
+		}
+	}
+	if opts.PreferHTTP2 {
+		transport.ForceAttemptHTTP2 = true
+		transport.TLSClientConfig = &tls.Config{NextProtos: []string{"h2", "http/1.1"}}
+	}
+	if opts.SNI != "" {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.ServerName = opts.SNI
+	}
+	if len(opts.Pins) > 0 {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.VerifyPeerCertificate = verifyCertificatePins(opts.Pins)
+	}
+	if len(opts.Resolve) > 0 || len(opts.ConnectTo) > 0 {
+		dialer := &net.Dialer{}
+		transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			if override, ok := opts.ConnectTo[addr]; ok {
+				addr = override
+			}
+			if override, ok := opts.Resolve[addr]; ok {
+				addr = override
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}
+	}
+	if proxyHandshakesTLSItself {
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		proxyTLSConfig := &tls.Config{
+			RootCAs:            opts.ProxyCACert,
+			InsecureSkipVerify: opts.ProxyInsecureSkipVerify,
+			ServerName:         opts.Proxy.Hostname(),
+		}
+		transport.DialContext = dialProxyTLS(dial, proxyTLSConfig)
+	}
+	if opts.FailEarly {
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		timeout := opts.FailEarlyTimeout
+		if timeout <= 0 {
+			timeout = defaultFailEarlyTimeout
+		}
+		transport.DialContext = withFailEarly(dial, timeout)
+	}
+	if opts.TimeoutConnect > 0 {
+		dial := transport.DialContext
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		transport.DialContext = withConnectTimeout(dial, opts.TimeoutConnect)
+	}
+	timeout := opts.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	c := &Client{
+		HTTPClient: &http.Client{
+			Timeout:   timeout,
+			Transport: transport,
+		},
+		Options: opts,
+	}
+	c.HTTPClient.CheckRedirect = c.checkRedirect
+	return c
+}
+
+// checkRedirect implements http.Client.CheckRedirect: it caps the
+// redirect chain length, notes an https->http downgrade in
+// redirectWarnings, and, when Options.LocationTrusted is set, restores
+// the Authorization and Cookie headers net/http's default redirect
+// handling already stripped for a cross-host hop.
+func (c *Client) checkRedirect(req *http.Request, via []*http.Request) error {
+	maxRedirects := c.Options.MaxRedirects
+	if maxRedirects <= 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+
+	c.redirectCount++
+
+	prev := via[len(via)-1]
+	if prev.URL.Scheme == "https" && req.URL.Scheme == "http" {
+		c.redirectWarnings = append(c.redirectWarnings, fmt.Sprintf("redirect from %s downgraded https to http", prev.URL))
+	}
+
+	if c.Options.LocationTrusted && prev.URL.Host != req.URL.Host {
+		first := via[0]
+		if auth := first.Header.Get("Authorization"); auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		if cookie := first.Header.Get("Cookie"); cookie != "" {
+			req.Header.Set("Cookie", cookie)
+		}
+	}
+
+	for _, disallowed := range c.Options.DisallowedRedirectHosts {
+		if strings.EqualFold(req.URL.Hostname(), disallowed) {
+			return fmt.Errorf("redirect to disallowed host %q (--no-redirect-to)", req.URL.Hostname())
+		}
+	}
+
+	return nil
+}
+
+// baseContext returns Options.Context, or context.Background() when it's
+// unset, the starting point every outgoing request's context is built
+// from before MaxTime's deadline is layered on top.
+func (c *Client) baseContext() context.Context {
+	if c.Options.Context != nil {
+		return c.Options.Context
+	}
+	return context.Background()
+}
+
+// Do sends req and reads the full response body into memory.
+func (c *Client) Do(req *models.Request) (*models.HTTPResponse, error) {
+	switch {
+	case c.Options.NTLM != nil:
+		return c.doNTLM(req)
+	case c.Options.Digest != nil:
+		return c.doDigest(req)
+	}
+
+	httpReq, err := req.HTTPRequest(c.Options.HeaderCase)
+	if err != nil {
+		return nil, err
+	}
+	httpReq = httpReq.WithContext(c.baseContext())
+	if c.Options.MaxTime > 0 {
+		ctx, cancel := context.WithTimeout(httpReq.Context(), c.Options.MaxTime)
+		defer cancel()
+		httpReq = httpReq.WithContext(ctx)
+	}
+	return c.send(httpReq, req.Body)
+}
+
+// doNTLM performs the NTLM Type 1/2/3 handshake (MS-NLMP) before
+// sending req for real. The Type 1 and Type 3 legs must land on the
+// same TCP connection the server issued its challenge on; this relies
+// on the Transport's ordinary keep-alive connection reuse rather than
+// pinning a connection explicitly, since nothing else in this codebase
+// reaches that low-level, and an uncontended Transport reuses its one
+// open connection for the very next request to the same host anyway.
+func (c *Client) doNTLM(req *models.Request) (*models.HTTPResponse, error) {
+	creds := c.Options.NTLM
+
+	negotiateReq, err := req.HTTPRequest(c.Options.HeaderCase)
+	if err != nil {
+		return nil, err
+	}
+	// The negotiate leg only needs to reach the server and provoke a
+	// challenge; the real body goes out on the authenticated leg below.
+	negotiateReq.Body = http.NoBody
+	negotiateReq.ContentLength = 0
+	negotiateReq.Header.Del("Content-Length")
+	negotiateReq.Header.Set("Authorization", "NTLM "+base64.StdEncoding.EncodeToString(ntlm.NegotiateMessage()))
+	negotiateReq = negotiateReq.WithContext(c.baseContext())
+
+	challengeResp, err := c.HTTPClient.Do(negotiateReq)
+	if err != nil {
+		return nil, translateResponseError(err)
+	}
+	challengeResp.Body.Close()
+
+	challengeHeader := ntlmChallengeHeader(challengeResp.Header.Values("Www-Authenticate"))
+	if challengeHeader == "" {
+		return nil, fmt.Errorf("ntlm: server did not issue a Type 2 challenge (got status %s)", challengeResp.Status)
+	}
+	raw, err := base64.StdEncoding.DecodeString(challengeHeader)
+	if err != nil {
+		return nil, fmt.Errorf("ntlm: malformed Type 2 challenge: %w", err)
+	}
+	challenge, err := ntlm.ParseChallengeMessage(raw)
+	if err != nil {
+		return nil, fmt.Errorf("ntlm: %w", err)
+	}
+
+	authenticate, err := ntlm.AuthenticateMessage(challenge, creds.Domain, creds.User, creds.Password, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("ntlm: %w", err)
+	}
+
+	authReq, err := req.HTTPRequest(c.Options.HeaderCase)
+	if err != nil {
+		return nil, err
+	}
+	authReq.Header.Set("Authorization", "NTLM "+base64.StdEncoding.EncodeToString(authenticate))
+	authReq = authReq.WithContext(c.baseContext())
+	if c.Options.MaxTime > 0 {
+		ctx, cancel := context.WithTimeout(authReq.Context(), c.Options.MaxTime)
+		defer cancel()
+		authReq = authReq.WithContext(ctx)
+	}
+
+	return c.send(authReq, req.Body)
+}
+
+// ntlmChallengeHeader returns the base64 Type 2 message from a
+// "Www-Authenticate: NTLM <blob>" value, or "" if the server sent a
+// bare "NTLM" (inviting the handshake to start, not continue it) or no
+// NTLM scheme at all.
+func ntlmChallengeHeader(values []string) string {
+	for _, v := range values {
+		scheme, blob, ok := strings.Cut(v, " ")
+		if ok && strings.EqualFold(scheme, "NTLM") && blob != "" {
+			return blob
+		}
+	}
+	return ""
+}
+
+// doDigest performs the Digest challenge/response handshake (RFC 7616)
+// before sending req for real. Unlike NTLM's Type 2 challenge, a
+// Digest nonce isn't tied to the TCP connection that issued it, so the
+// authenticated leg doesn't depend on Transport reusing the probe
+// leg's connection to succeed.
+func (c *Client) doDigest(req *models.Request) (*models.HTTPResponse, error) {
+	creds := c.Options.Digest
+
+	probeReq, err := req.HTTPRequest(c.Options.HeaderCase)
+	if err != nil {
+		return nil, err
+	}
+	// The probe leg only needs to reach the server and provoke a
+	// challenge; the real body goes out on the authenticated leg below.
+	probeReq.Body = http.NoBody
+	probeReq.ContentLength = 0
+	probeReq.Header.Del("Content-Length")
+	probeReq = probeReq.WithContext(c.baseContext())
+
+	probeResp, err := c.HTTPClient.Do(probeReq)
+	if err != nil {
+		return nil, translateResponseError(err)
+	}
+	probeResp.Body.Close()
+
+	challengeHeader := digestChallengeHeader(probeResp.Header.Values("Www-Authenticate"))
+	if challengeHeader == "" {
+		return nil, fmt.Errorf("digest: server did not issue a Digest challenge (got status %s)", probeResp.Status)
+	}
+	challenge, err := digest.ParseChallenge(challengeHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	authReq, err := req.HTTPRequest(c.Options.HeaderCase)
+	if err != nil {
+		return nil, err
+	}
+	authHeader, err := digest.Authorization(challenge, digest.Credentials{Username: creds.User, Password: creds.Password}, authReq.Method, authReq.URL.RequestURI(), 1)
+	if err != nil {
+		return nil, err
+	}
+	authReq.Header.Set("Authorization", authHeader)
+	authReq = authReq.WithContext(c.baseContext())
+	if c.Options.MaxTime > 0 {
+		ctx, cancel := context.WithTimeout(authReq.Context(), c.Options.MaxTime)
+		defer cancel()
+		authReq = authReq.WithContext(ctx)
+	}
+
+	return c.send(authReq, req.Body)
+}
+
+// digestChallengeHeader returns the first "Www-Authenticate: Digest
+// ..." value, or "" if the server didn't send a Digest challenge.
+func digestChallengeHeader(values []string) string {
+	for _, v := range values {
+		if scheme, _, ok := strings.Cut(v, " "); ok && strings.EqualFold(scheme, "Digest") {
+			return v
+		}
+	}
+	return ""
+}
+
+// send performs the round trip for an already-built request and
+// translates the result into a Response, shared by Do and doNTLM's
+// final, authenticated leg.
+func (c *Client) send(httpReq *http.Request, traceBody string) (*models.HTTPResponse, error) {
+	c.redirectWarnings = nil
+	c.redirectCount = 0
+
+	if c.Options.Trace != nil {
+		writeTraceRequest(c.Options.Trace, httpReq, traceBody, c.Options.TraceShowSecrets)
+	}
+
+	start := time.Now()
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		if c.Options.MaxTime > 0 && errors.Is(httpReq.Context().Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("exceeded max-time (%s)", c.Options.MaxTime)
+		}
+		var connErr *connectTimeoutError
+		if errors.As(err, &connErr) {
+			return nil, connErr
+		}
+		var unresolvedErr *unresolvedHostError
+		if errors.As(err, &unresolvedErr) {
+			return nil, unresolvedErr
+		}
+		if isTimeout(err) {
+			return nil, fmt.Errorf("read timeout after %s", c.HTTPClient.Timeout)
+		}
+		return nil, translateResponseError(err)
+	}
+	defer resp.Body.Close()
+
+	if c.Options.Trace != nil {
+		writeTraceTLS(c.Options.Trace, resp.TLS)
+	}
+
+	var body []byte
+	var truncated bool
+	if c.Options.Stream != nil {
+		writeStreamHeader(c.Options.Stream, resp)
+		body, err = streamBody(c.Options.Stream, resp.Body)
+	} else {
+		body, truncated, err = readBody(resp.Body, c.Options.MaxBodySize)
+	}
+	if err != nil {
+		if c.Options.MaxTime > 0 && errors.Is(httpReq.Context().Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("exceeded max-time (%s)", c.Options.MaxTime)
+		}
+		if isTimeout(err) {
+			return nil, fmt.Errorf("read timeout after %s", c.HTTPClient.Timeout)
+		}
+		return nil, err
+	}
+	elapsed := time.Since(start)
+
+	if c.Options.Trace != nil {
+		writeTraceResponse(c.Options.Trace, resp, body, c.Options.TraceShowSecrets)
+	}
+
+	finalURL := httpReq.URL.String()
+	if resp.Request != nil {
+		finalURL = resp.Request.URL.String()
+	}
+
+	bodyText, charsetWarning := decodeBodyCharset(body, resp.Header.Get("Content-Type"))
+
+	return &models.HTTPResponse{
+		Proto:            resp.Proto,
+		StatusCode:       resp.StatusCode,
+		Status:           resp.Status,
+		Header:           resp.Header,
+		Body:             bodyText,
+		RawBody:          body,
+		RedirectWarnings: c.redirectWarnings,
+		Redirects:        c.redirectCount,
+		FinalURL:         finalURL,
+		CharsetWarning:   charsetWarning,
+		Timing:           elapsed,
+		Cookies:          resp.Cookies(),
+		Truncated:        truncated,
+		// resp.Trailer is only populated by net/http once resp.Body has
+		// been read to EOF, which readBody just did above unless
+		// Truncated stopped it short.
+		Trailer: resp.Trailer,
+	}, nil
+}
+
+// decodeBodyCharset decodes body to UTF-8 per the charset named in
+// contentType's "charset" parameter, for display in Body; RawBody keeps
+// body untouched regardless. A charset Decode doesn't support (or a
+// malformed Content-Type) leaves bodyText as body's raw bytes and
+// returns a warning describing why, rather than silently mangling or
+// dropping data the caller might still need.
+func decodeBodyCharset(body []byte, contentType string) (bodyText string, warning string) {
+	if contentType == "" {
+		return string(body), ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil || params["charset"] == "" {
+		return string(body), ""
+	}
+	name := params["charset"]
+	decoded, ok := charset.Decode(body, name)
+	if !ok {
+		return string(body), fmt.Sprintf("unsupported charset %q in Content-Type, showing raw bytes", name)
+	}
+	return decoded, ""
+}
+
+// readBody reads r fully, unless maxBytes is positive, in which case it
+// reads at most maxBytes and reports whether the body was longer than
+// that. This caps memory use for the Content-Length, chunked, and
+// close-delimited response bodies alike, since all of them reach here
+// as the same io.Reader.
+func readBody(r io.Reader, maxBytes int64) (body []byte, truncated bool, err error) {
+	if maxBytes <= 0 {
+		body, err = io.ReadAll(r)
+		return body, false, err
+	}
+
+	limited := io.LimitReader(r, maxBytes+1)
+	body, err = io.ReadAll(limited)
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(body)) > maxBytes {
+		return body[:maxBytes], true, nil
+	}
+	return body, false, nil
+}
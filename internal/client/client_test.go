@@ -0,0 +1,303 @@
+package client
+
+import (
+	"bufio"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+func TestDo_TruncatesContentLengthBodyAtMaxBodySize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("0123456789"))
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	c := New(Options{MaxBodySize: 4})
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Truncated {
+		t.Error("expected Truncated to be true")
+	}
+	if resp.Body != "0123" {
+		t.Errorf("expected body truncated to 4 bytes, got %q", resp.Body)
+	}
+}
+
+func TestDo_TruncatesChunkedBodyAtMaxBodySize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		for _, chunk := range []string{"ab", "cd", "ef"} {
+			w.Write([]byte(chunk))
+			flusher.Flush()
+		}
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	c := New(Options{MaxBodySize: 3})
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Truncated || resp.Body != "abc" {
+		t.Errorf("expected truncated body %q, got %q (truncated=%v)", "abc", resp.Body, resp.Truncated)
+	}
+}
+
+func TestDo_MaxTimeFailsOnTricklingServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		flusher := w.(http.Flusher)
+		for i := 0; i < 20; i++ {
+			w.Write([]byte("x"))
+			flusher.Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	c := New(Options{MaxTime: 50 * time.Millisecond})
+
+	_, err := c.Do(req)
+	if err == nil {
+		t.Fatal("expected an error from exceeding --max-time")
+	}
+	if !strings.Contains(err.Error(), "max-time") {
+		t.Errorf("expected the error to mention max-time, got %v", err)
+	}
+}
+
+func TestDo_ResolveOverridesDialAddrButKeepsHostHeader(t *testing.T) {
+	var gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	srvURL, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, err := net.SplitHostPort(srvURL.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fakeHost := "fake.example.internal:" + port
+	req := newTestRequest(t, "http://"+fakeHost)
+	c := New(Options{Resolve: map[string]string{fakeHost: srvURL.Host}})
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected the request to actually reach the test server, got body %q", resp.Body)
+	}
+	if gotHost != fakeHost {
+		t.Errorf("expected the server to see Host %q, got %q", fakeHost, gotHost)
+	}
+}
+
+func TestDo_ZeroMaxBodySizeIsUnlimited(t *testing.T) {
+	body := strings.Repeat("x", 1<<16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	c := New(Options{MaxBodySize: 0})
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Truncated {
+		t.Error("expected no truncation with MaxBodySize=0")
+	}
+	if len(resp.Body) != len(body) {
+		t.Errorf("expected full body of length %d, got %d", len(body), len(resp.Body))
+	}
+}
+
+// TestDo_HTTP10ResponseWithoutContentLengthReadsUntilClose covers a
+// server that speaks HTTP/1.0 close-delimited framing: no
+// Content-Length, no chunked encoding, just a body followed by the
+// connection closing. httptest.NewServer always negotiates HTTP/1.1, so
+// this test speaks raw TCP instead.
+func TestDo_HTTP10ResponseWithoutContentLengthReadsUntilClose(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil || strings.TrimRight(line, "\r\n") == "" {
+				break
+			}
+		}
+		conn.Write([]byte("HTTP/1.0 200 OK\r\n\r\nhello world"))
+	}()
+
+	req := models.New()
+	req.Method = http.MethodGet
+	req.Proto = "HTTP/1.0"
+	u, err := url.Parse("http://" + ln.Addr().String() + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.URL = u
+
+	c := New(Options{})
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "hello world" {
+		t.Errorf("expected body %q, got %q", "hello world", resp.Body)
+	}
+}
+
+func TestDo_PreservesMultipleSetCookieHeaders(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Set-Cookie", "session=abc")
+		w.Header().Add("Set-Cookie", "theme=dark")
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	c := New(Options{})
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header["Set-Cookie"]; len(got) != 2 {
+		t.Fatalf("expected 2 Set-Cookie header values, got %v", got)
+	}
+	if len(resp.Cookies) != 2 {
+		t.Fatalf("expected 2 parsed cookies, got %v", resp.Cookies)
+	}
+	names := map[string]bool{resp.Cookies[0].Name: true, resp.Cookies[1].Name: true}
+	if !names["session"] || !names["theme"] {
+		t.Errorf("expected cookies named session and theme, got %v", resp.Cookies)
+	}
+}
+
+func TestDo_TraceRedactsSecretsByDefault(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=xyz")
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	var trace strings.Builder
+	c := New(Options{Trace: &trace})
+	if _, err := c.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	out := trace.String()
+	if strings.Contains(out, "secret-token") {
+		t.Errorf("expected Authorization value to be redacted, got trace:\n%s", out)
+	}
+	if !strings.Contains(out, "> Authorization: [redacted]") {
+		t.Errorf("expected a redacted Authorization line, got trace:\n%s", out)
+	}
+	if !strings.Contains(out, "> GET / HTTP/1.1") {
+		t.Errorf("expected the outgoing request line, got trace:\n%s", out)
+	}
+	if !strings.Contains(out, "< HTTP/1.1 200 OK") {
+		t.Errorf("expected the response status line, got trace:\n%s", out)
+	}
+}
+
+func TestDo_TraceShowSecretsDisablesRedaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	req.Header.Set("Authorization", "Bearer secret-token")
+
+	var trace strings.Builder
+	c := New(Options{Trace: &trace, TraceShowSecrets: true})
+	if _, err := c.Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(trace.String(), "secret-token") {
+		t.Errorf("expected Authorization value to be visible with TraceShowSecrets, got trace:\n%s", trace.String())
+	}
+}
+
+func TestDo_NegotiatesHTTP2OverALPN(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "proto=%s", r.Proto)
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	req.Proto = "HTTP/2"
+
+	c := New(Options{PreferHTTP2: true})
+	transport := c.HTTPClient.Transport.(*http.Transport)
+	transport.TLSClientConfig.RootCAs = x509.NewCertPool()
+	transport.TLSClientConfig.RootCAs.AddCert(srv.Certificate())
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Proto != "HTTP/2.0" {
+		t.Errorf("expected negotiated HTTP/2.0, got %q", resp.Proto)
+	}
+	if resp.Body != "proto=HTTP/2.0" {
+		t.Errorf("expected server to see HTTP/2.0 too, got body %q", resp.Body)
+	}
+}
+
+func newTestRequest(t *testing.T, rawURL string) *models.Request {
+	t.Helper()
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := models.New()
+	req.Method = http.MethodGet
+	req.URL = u
+	return req
+}
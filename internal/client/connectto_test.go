@@ -0,0 +1,42 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// TestDo_ConnectToRemapsDialAddrButKeepsHostHeader asserts --connect-to
+// (client.Options.ConnectTo) remaps a logical backend name to the
+// testserver's own host:port while the Host header the server sees is
+// still the logical name, mirroring curl's --connect-to.
+func TestDo_ConnectToRemapsDialAddrButKeepsHostHeader(t *testing.T) {
+	var gotHost string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	srvURL, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	logicalHost := "backend-pool.internal:80"
+	req := newTestRequest(t, "http://"+logicalHost)
+	c := New(Options{ConnectTo: map[string]string{logicalHost: srvURL.Host}})
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected the request to actually reach the test server, got body %q", resp.Body)
+	}
+	if gotHost != logicalHost {
+		t.Errorf("expected the server to see Host %q, got %q", logicalHost, gotHost)
+	}
+}
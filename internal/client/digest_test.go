@@ -0,0 +1,121 @@
+package client
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// parseAuthorizationParams splits a Digest Authorization header's
+// comma-separated "key=value"/"key=\"value\"" parameter list.
+func parseAuthorizationParams(t *testing.T, authorization string) map[string]string {
+	t.Helper()
+	scheme, params, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Digest") {
+		t.Fatalf("malformed Authorization header %q", authorization)
+	}
+	values := make(map[string]string)
+	for _, part := range strings.Split(params, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+	return values
+}
+
+// TestDo_DigestPerformsHandshakeThenSendsTheRealRequest sets up a mock
+// server that challenges the unauthenticated probe with a Digest
+// WWW-Authenticate header and only serves the real response once the
+// following request's Authorization header carries a response hash
+// that matches RFC 7616's computation.
+func TestDo_DigestPerformsHandshakeThenSendsTheRealRequest(t *testing.T) {
+	var gotBody string
+	var legs int
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" {
+			w.Header().Set("Www-Authenticate", `Digest realm="test", qop="auth", nonce="abc123", algorithm=MD5`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		legs++
+		values := parseAuthorizationParams(t, authorization)
+
+		ha1 := md5Hex("alice:test:hunter2")
+		ha2 := md5Hex("POST:/secure")
+		want := md5Hex(strings.Join([]string{ha1, "abc123", values["nc"], values["cnonce"], "auth", ha2}, ":"))
+		if values["response"] != want {
+			t.Errorf("response = %q, want %q", values["response"], want)
+		}
+		if values["nc"] != "00000001" {
+			t.Errorf("nc = %q, want 00000001", values["nc"])
+		}
+
+		b, _ := io.ReadAll(r.Body)
+		gotBody = string(b)
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("authenticated"))
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL+"/secure")
+	req.Method = http.MethodPost
+	req.Body = "hello"
+	req.SetHeader("Content-Length", "5")
+
+	c := New(Options{
+		Timeout: 2 * time.Second,
+		Digest:  &DigestCredentials{User: "alice", Password: "hunter2"},
+	})
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the final response to be 200, got %d", resp.StatusCode)
+	}
+	if resp.Body != "authenticated" {
+		t.Errorf("expected the real response body, got %q", resp.Body)
+	}
+	if legs != 1 {
+		t.Errorf("expected exactly one authenticated leg, got %d", legs)
+	}
+	if gotBody != "hello" {
+		t.Errorf("expected the request body on the authenticated leg, got %q", gotBody)
+	}
+}
+
+// TestDo_DigestErrorsWithoutAChallenge asserts a server that never
+// issues a Digest challenge produces a clear error instead of silently
+// sending an unauthenticated request through.
+func TestDo_DigestErrorsWithoutAChallenge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL+"/secure")
+	c := New(Options{
+		Timeout: 2 * time.Second,
+		Digest:  &DigestCredentials{User: "alice", Password: "hunter2"},
+	})
+
+	if _, err := c.Do(req); err == nil {
+		t.Fatal("expected an error when the server never issues a Digest challenge")
+	}
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
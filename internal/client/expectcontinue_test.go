@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// rawExpectContinueServer starts a bare TCP listener (rather than
+// httptest.Server) so the test controls exactly when, or whether, a 100
+// Continue interim response is written, ahead of the real response
+// respond returns.
+func rawExpectContinueServer(t *testing.T, respond func(t *testing.T, br *bufio.Reader, conn net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		respond(t, bufio.NewReader(conn), conn)
+	}()
+	return "http://" + ln.Addr().String() + "/"
+}
+
+// readRequestHeaders reads request line and headers up to the blank line
+// terminating them, without touching the body.
+func readRequestHeaders(t *testing.T, br *bufio.Reader) []string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "" {
+			return lines
+		}
+		lines = append(lines, trimmed)
+	}
+}
+
+// TestDo_ExpectContinueSendsBodyAfter100Continue asserts the client
+// waits for a 100 Continue interim response before streaming the body,
+// then delivers the body and reads the real, final response.
+func TestDo_ExpectContinueSendsBodyAfter100Continue(t *testing.T) {
+	url := rawExpectContinueServer(t, func(t *testing.T, br *bufio.Reader, conn net.Conn) {
+		headers := readRequestHeaders(t, br)
+		hasExpect := false
+		for _, h := range headers {
+			if strings.EqualFold(h, "Expect: 100-continue") {
+				hasExpect = true
+			}
+		}
+		if !hasExpect {
+			t.Error("expected the request to carry Expect: 100-continue")
+		}
+
+		if _, err := conn.Write([]byte("HTTP/1.1 100 Continue\r\n\r\n")); err != nil {
+			t.Fatal(err)
+		}
+
+		body := make([]byte, len("payload"))
+		if _, err := io.ReadFull(br, body); err != nil {
+			t.Fatalf("expected the body after 100 Continue, got: %v", err)
+		}
+		if string(body) != "payload" {
+			t.Errorf("expected body %q, got %q", "payload", body)
+		}
+
+		conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 2\r\n\r\nok"))
+	})
+
+	req := newTestRequest(t, url)
+	req.Method = http.MethodPost
+	req.Body = "payload"
+	req.SetHeader("Expect", "100-continue")
+
+	resp, err := New(Options{}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 200 {
+		t.Errorf("expected status 200, got %d", resp.StatusCode)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected body %q, got %q", "ok", resp.Body)
+	}
+}
+
+// TestDo_ExpectContinueSkipsBodyOnFinalErrorStatus asserts that when the
+// server answers with a final status instead of 100 Continue, the client
+// surfaces that response without ever having to send the body.
+func TestDo_ExpectContinueSkipsBodyOnFinalErrorStatus(t *testing.T) {
+	url := rawExpectContinueServer(t, func(t *testing.T, br *bufio.Reader, conn net.Conn) {
+		readRequestHeaders(t, br)
+		conn.Write([]byte("HTTP/1.1 417 Expectation Failed\r\nContent-Length: 0\r\nConnection: close\r\n\r\n"))
+	})
+
+	req := newTestRequest(t, url)
+	req.Method = http.MethodPost
+	req.Body = "payload"
+	req.SetHeader("Expect", "100-continue")
+
+	resp, err := New(Options{}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != 417 {
+		t.Errorf("expected status 417, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// defaultFailEarlyTimeout bounds FailEarly's DNS lookup when
+// Options.FailEarlyTimeout is 0: short, since the whole point is to
+// catch an obviously-dead hostname before spending the usual connect
+// timeout on it.
+const defaultFailEarlyTimeout = 2 * time.Second
+
+// withFailEarly wraps dial with a DNS lookup of addr's host, returning a
+// clear *unresolvedHostError immediately when it comes back NXDOMAIN,
+// rather than letting dial discover the same thing itself after however
+// much of the connect timeout is left. addr's host is left to dial
+// unchanged (and the lookup result discarded) when it's already a
+// literal IP, or when the lookup times out or fails for any reason other
+// than the host simply not existing, since only a definite NXDOMAIN is
+// worth failing early over.
+func withFailEarly(dial dialContextFunc, timeout time.Duration) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host := addr
+		if h, _, err := net.SplitHostPort(addr); err == nil {
+			host = h
+		}
+		if net.ParseIP(host) == nil {
+			lookupCtx, cancel := context.WithTimeout(ctx, timeout)
+			_, err := net.DefaultResolver.LookupHost(lookupCtx, host)
+			cancel()
+			var dnsErr *net.DNSError
+			if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+				return nil, &unresolvedHostError{host: host}
+			}
+		}
+		return dial(ctx, network, addr)
+	}
+}
+
+// unresolvedHostError reports that FailEarly's lookup came back
+// NXDOMAIN, distinct from the generic "connection refused" or "i/o
+// timeout" a failed dial would otherwise produce for the same typo'd
+// hostname.
+type unresolvedHostError struct {
+	host string
+}
+
+func (e *unresolvedHostError) Error() string {
+	return fmt.Sprintf("could not resolve host %q", e.host)
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithFailEarly_ReturnsUnresolvedHostErrorForANonexistentHostname(t *testing.T) {
+	called := false
+	dial := withFailEarly(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		called = true
+		return nil, nil
+	}, 5*time.Second)
+
+	_, err := dial(context.Background(), "tcp", "definitely-invalid-host-name.invalid:443")
+
+	var unresolvedErr *unresolvedHostError
+	if !errors.As(err, &unresolvedErr) {
+		t.Fatalf("expected an *unresolvedHostError, got %v", err)
+	}
+	if !strings.Contains(unresolvedErr.Error(), "definitely-invalid-host-name.invalid") {
+		t.Errorf("expected the error to name the host, got %v", unresolvedErr)
+	}
+	if called {
+		t.Error("expected dial not to be called once the lookup came back NXDOMAIN")
+	}
+}
+
+func TestWithFailEarly_DialsNormallyForAResolvableHostname(t *testing.T) {
+	called := false
+	dial := withFailEarly(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		called = true
+		return nil, nil
+	}, 5*time.Second)
+
+	if _, err := dial(context.Background(), "tcp", "localhost:80"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected dial to be called for a resolvable hostname")
+	}
+}
+
+func TestWithFailEarly_DialsNormallyForALiteralIP(t *testing.T) {
+	called := false
+	dial := withFailEarly(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		called = true
+		return nil, nil
+	}, 5*time.Second)
+
+	if _, err := dial(context.Background(), "tcp", "127.0.0.1:80"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !called {
+		t.Error("expected dial to be called for a literal IP without any lookup")
+	}
+}
+
+func TestDo_FailEarlyReportsAnUnresolvableHostDistinctlyFromAConnectionError(t *testing.T) {
+	req := newTestRequest(t, "http://definitely-invalid-host-name.invalid/")
+	c := New(Options{FailEarly: true})
+
+	_, err := c.Do(req)
+	if err == nil {
+		t.Fatal("expected an error for an unresolvable host")
+	}
+	if !strings.Contains(err.Error(), "could not resolve host") {
+		t.Errorf("expected a \"could not resolve host\" error, got %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// readRawHeaderLines accepts one connection on ln, reads the request
+// line and headers verbatim (before net/http's server-side parsing
+// would canonicalize them), and returns the header lines.
+func readRawHeaderLines(t *testing.T, ln net.Listener) []string {
+	t.Helper()
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		trimmed := strings.TrimRight(line, "\r\n")
+		if trimmed == "" {
+			break
+		}
+		lines = append(lines, trimmed)
+	}
+	conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 0\r\n\r\n"))
+	return lines
+}
+
+func mixedCaseHeaderRequest(t *testing.T, ln net.Listener) *models.Request {
+	t.Helper()
+	req := models.New()
+	req.Method = http.MethodGet
+	u, err := url.Parse("http://" + ln.Addr().String() + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.URL = u
+	req.Header.Add("x-REQUEST-id", "abc123")
+	req.RawHeaders = append(req.RawHeaders, models.RawHeader{Name: "x-REQUEST-id", Value: "abc123"})
+	return req
+}
+
+func TestDo_HeaderCasePreserveSendsOriginalCasingOnTheWire(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	req := mixedCaseHeaderRequest(t, ln)
+	done := make(chan []string, 1)
+	go func() { done <- readRawHeaderLines(t, ln) }()
+
+	if _, err := New(Options{HeaderCase: "preserve"}).Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if lines := <-done; !containsHeaderLine(lines, "x-REQUEST-id: abc123") {
+		t.Errorf("expected the original casing on the wire, got %v", lines)
+	}
+}
+
+func TestDo_HeaderCaseCanonicalSendsCanonicalCasingOnTheWire(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	req := mixedCaseHeaderRequest(t, ln)
+	done := make(chan []string, 1)
+	go func() { done <- readRawHeaderLines(t, ln) }()
+
+	if _, err := New(Options{HeaderCase: "canonical"}).Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if lines := <-done; !containsHeaderLine(lines, "X-Request-Id: abc123") {
+		t.Errorf("expected canonical casing on the wire, got %v", lines)
+	}
+}
+
+func TestDo_HeaderCaseLowerSendsLowercaseCasingOnTheWire(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	req := mixedCaseHeaderRequest(t, ln)
+	done := make(chan []string, 1)
+	go func() { done <- readRawHeaderLines(t, ln) }()
+
+	if _, err := New(Options{HeaderCase: "lower"}).Do(req); err != nil {
+		t.Fatal(err)
+	}
+	if lines := <-done; !containsHeaderLine(lines, "x-request-id: abc123") {
+		t.Errorf("expected lowercase casing on the wire, got %v", lines)
+	}
+}
+
+func containsHeaderLine(lines []string, want string) bool {
+	for _, line := range lines {
+		if line == want {
+			return true
+		}
+	}
+	return false
+}
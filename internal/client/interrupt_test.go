@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// TestDo_AbortsWhenOptionsContextIsCanceled asserts Options.Context is
+// wired into the outgoing request, so canceling it (as the CLI's
+// SIGINT/SIGTERM handler does) aborts an in-flight request instead of
+// letting it run to completion.
+func TestDo_AbortsWhenOptionsContextIsCanceled(t *testing.T) {
+	unblock := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+		fmt.Fprint(w, "too late")
+	}))
+	defer srv.Close()
+	defer close(unblock)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	req := newTestRequest(t, srv.URL+"/")
+	_, err := New(Options{Context: ctx}).Do(req)
+	if err == nil {
+		t.Fatal("expected canceling Options.Context to abort the request")
+	}
+}
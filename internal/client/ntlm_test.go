@@ -0,0 +1,122 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ntlmMessageType returns the MessageType field of a base64-encoded
+// NTLM message from an Authorization: NTLM <blob> header.
+func ntlmMessageType(t *testing.T, authorization string) uint32 {
+	t.Helper()
+	_, blob, ok := strings.Cut(authorization, " ")
+	if !ok {
+		t.Fatalf("malformed Authorization header %q", authorization)
+	}
+	raw, err := base64.StdEncoding.DecodeString(blob)
+	if err != nil {
+		t.Fatalf("malformed base64 in Authorization header: %v", err)
+	}
+	return binary.LittleEndian.Uint32(raw[8:12])
+}
+
+// buildTestChallenge returns a minimal, valid NTLM Type 2 message with
+// the given 8-byte server challenge and no target info.
+func buildTestChallenge(serverChallenge [8]byte) []byte {
+	const headerLen = 48
+	msg := make([]byte, headerLen)
+	copy(msg[0:8], "NTLMSSP\x00")
+	binary.LittleEndian.PutUint32(msg[8:12], 2)
+	copy(msg[24:32], serverChallenge[:])
+	return msg
+}
+
+// TestDo_NTLMPerformsHandshakeThenSendsTheRealRequest sets up a mock
+// server that challenges the first (Type 1) leg with a Type 2 message
+// and only serves the real response once it sees a Type 3 reply on the
+// following request.
+func TestDo_NTLMPerformsHandshakeThenSendsTheRealRequest(t *testing.T) {
+	var legs []uint32
+	var gotBody string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization := r.Header.Get("Authorization")
+		if authorization == "" || !strings.HasPrefix(authorization, "NTLM ") {
+			w.Header().Set("Www-Authenticate", "NTLM")
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		msgType := ntlmMessageType(t, authorization)
+		legs = append(legs, msgType)
+
+		switch msgType {
+		case 1:
+			challenge := buildTestChallenge([8]byte{1, 2, 3, 4, 5, 6, 7, 8})
+			w.Header().Set("Www-Authenticate", "NTLM "+base64.StdEncoding.EncodeToString(challenge))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			b, _ := io.ReadAll(r.Body)
+			gotBody = string(b)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte("authenticated"))
+		default:
+			t.Errorf("unexpected NTLM message type %d", msgType)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL+"/secure")
+	req.Method = http.MethodPost
+	req.Body = "hello"
+	req.SetHeader("Content-Length", "5")
+
+	c := New(Options{
+		Timeout: 2 * time.Second,
+		NTLM:    &NTLMCredentials{Domain: "EXAMPLE", User: "alice", Password: "hunter2"},
+	})
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the final response to be 200, got %d", resp.StatusCode)
+	}
+	if resp.Body != "authenticated" {
+		t.Errorf("expected the real response body, got %q", resp.Body)
+	}
+	if want := []uint32{1, 3}; len(legs) != len(want) || legs[0] != want[0] || legs[1] != want[1] {
+		t.Errorf("expected NTLM message types [1 3] in order, got %v", legs)
+	}
+	if gotBody != "hello" {
+		t.Errorf("expected the request body on the authenticated leg, got %q", gotBody)
+	}
+}
+
+// TestDo_NTLMErrorsWithoutAChallenge asserts a server that never issues
+// a Type 2 challenge produces a clear error instead of hanging or
+// silently sending an unauthenticated request through.
+func TestDo_NTLMErrorsWithoutAChallenge(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL+"/secure")
+	c := New(Options{
+		Timeout: 2 * time.Second,
+		NTLM:    &NTLMCredentials{Domain: "EXAMPLE", User: "alice", Password: "hunter2"},
+	})
+
+	if _, err := c.Do(req); err == nil {
+		t.Fatal("expected an error when the server never issues a Type 2 challenge")
+	}
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+)
+
+// dialProxyTLS wraps dial so its plain TCP connection to an https://
+// proxy (disguised as http-scheme in Transport.Proxy so net/http treats
+// the dial as plaintext; see New) is immediately upgraded to TLS with
+// proxyTLSConfig, before net/http runs its own CONNECT-and-tunnel logic
+// on top of the result. Errors are prefixed "proxy certificate" so they
+// read distinctly from a failure verifying the origin server's own
+// certificate later in the same request.
+func dialProxyTLS(dial func(ctx context.Context, network, addr string) (net.Conn, error), proxyTLSConfig *tls.Config) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		tlsConn := tls.Client(conn, proxyTLSConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy certificate: %w", err)
+		}
+		return tlsConn, nil
+	}
+}
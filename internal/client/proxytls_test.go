@@ -0,0 +1,133 @@
+package client
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// newConnectProxy returns a TLS-terminating mock forward proxy: it
+// accepts a CONNECT request, dials the requested authority in the clear,
+// and splices the two connections together, exactly like a real
+// corporate MITM proxy tunneling an HTTPS request it doesn't otherwise
+// touch.
+func newConnectProxy(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "expected CONNECT", http.StatusMethodNotAllowed)
+			return
+		}
+		dest, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer dest.Close()
+
+		clientConn, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			return
+		}
+		defer clientConn.Close()
+
+		if _, err := clientConn.Write([]byte("HTTP/1.1 200 Connection Established\r\n\r\n")); err != nil {
+			return
+		}
+		go io.Copy(dest, clientConn)
+		io.Copy(clientConn, dest)
+	}))
+}
+
+func TestDo_ProxyInsecureSkipsTheProxysOwnCertificateVerification(t *testing.T) {
+	origin := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer origin.Close()
+
+	proxy := newConnectProxy(t)
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := newTestRequest(t, origin.URL)
+	c := New(Options{Proxy: proxyURL, ProxyInsecureSkipVerify: true})
+	trustTestServer(t, c, origin)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("expected --proxy-insecure to accept the proxy's untrusted certificate, got %v", err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected body %q, got %q", "ok", resp.Body)
+	}
+}
+
+func TestDo_ProxyCACertTrustsTheProxysCertificate(t *testing.T) {
+	origin := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer origin.Close()
+
+	proxy := newConnectProxy(t)
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proxyCACert := x509.NewCertPool()
+	proxyCACert.AddCert(proxy.Certificate())
+
+	req := newTestRequest(t, origin.URL)
+	c := New(Options{Proxy: proxyURL, ProxyCACert: proxyCACert})
+	trustTestServer(t, c, origin)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("expected --proxy-cacert to trust the proxy's certificate, got %v", err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected body %q, got %q", "ok", resp.Body)
+	}
+}
+
+// TestDo_UntrustedProxyCertificateFailsDistinctlyFromAServerCertificate
+// asserts an untrusted proxy certificate is reported as a "proxy
+// certificate" failure, not a generic or origin-server one, even though
+// the origin server's own certificate is independently trusted.
+func TestDo_UntrustedProxyCertificateFailsDistinctlyFromAServerCertificate(t *testing.T) {
+	origin := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer origin.Close()
+
+	proxy := newConnectProxy(t)
+	defer proxy.Close()
+
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := newTestRequest(t, origin.URL)
+	c := New(Options{Proxy: proxyURL, ProxyCACert: x509.NewCertPool()})
+	trustTestServer(t, c, origin)
+
+	_, err = c.Do(req)
+	if err == nil {
+		t.Fatal("expected the untrusted proxy certificate to fail the handshake")
+	}
+	if !strings.Contains(err.Error(), "proxy certificate") {
+		t.Errorf("expected a \"proxy certificate\"-prefixed error, got %v", err)
+	}
+}
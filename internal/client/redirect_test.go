@@ -0,0 +1,247 @@
+package client
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+// fakeHostname returns a Resolve override that makes srv reachable under
+// a distinct, made-up hostname instead of its real 127.0.0.1 address,
+// since net/http's own Authorization/Cookie stripping on redirect keys
+// off hostname alone and would treat two 127.0.0.1 servers on different
+// ports as the same host.
+func fakeHostname(t *testing.T, name string, srv *httptest.Server) (fakeURL string, resolve map[string]string) {
+	t.Helper()
+	u, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, port, err := net.SplitHostPort(u.Host)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fakeHostPort := name + ":" + port
+	return "http://" + fakeHostPort + "/", map[string]string{fakeHostPort: u.Host}
+}
+
+func TestDo_SameHostRedirectKeepsAuthorization(t *testing.T) {
+	var gotAuth string
+	var redirected bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			redirected = true
+			http.Redirect(w, r, "/end", http.StatusFound)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL+"/start")
+	req.SetHeader("Authorization", "Bearer secret")
+
+	resp, err := New(Options{}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !redirected {
+		t.Fatal("expected the request to be redirected")
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected the redirect to be followed, got body %q", resp.Body)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("expected Authorization to survive a same-host redirect, got %q", gotAuth)
+	}
+}
+
+func TestDo_CrossHostRedirectDropsAuthorization(t *testing.T) {
+	var gotAuth string
+	var gotAuthSet bool
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth, gotAuthSet = r.Header.Get("Authorization"), r.Header.Get("Authorization") != ""
+		fmt.Fprint(w, "ok")
+	}))
+	defer target.Close()
+	targetURL, targetResolve := fakeHostname(t, "target.example.internal", target)
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, targetURL, http.StatusFound)
+	}))
+	defer origin.Close()
+	originURL, originResolve := fakeHostname(t, "origin.example.internal", origin)
+
+	req := newTestRequest(t, originURL)
+	req.SetHeader("Authorization", "Bearer secret")
+
+	resolve := map[string]string{}
+	for k, v := range originResolve {
+		resolve[k] = v
+	}
+	for k, v := range targetResolve {
+		resolve[k] = v
+	}
+
+	resp, err := New(Options{Resolve: resolve}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected the redirect to be followed, got body %q", resp.Body)
+	}
+	if gotAuthSet {
+		t.Errorf("expected Authorization to be dropped on a cross-host redirect, got %q", gotAuth)
+	}
+}
+
+func TestDo_LocationTrustedKeepsAuthorizationAcrossHosts(t *testing.T) {
+	var gotAuth string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		fmt.Fprint(w, "ok")
+	}))
+	defer target.Close()
+	targetURL, targetResolve := fakeHostname(t, "target.example.internal", target)
+
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, targetURL, http.StatusFound)
+	}))
+	defer origin.Close()
+	originURL, originResolve := fakeHostname(t, "origin.example.internal", origin)
+
+	req := newTestRequest(t, originURL)
+	req.SetHeader("Authorization", "Bearer secret")
+
+	resolve := map[string]string{}
+	for k, v := range originResolve {
+		resolve[k] = v
+	}
+	for k, v := range targetResolve {
+		resolve[k] = v
+	}
+
+	resp, err := New(Options{LocationTrusted: true, Resolve: resolve}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected the redirect to be followed, got body %q", resp.Body)
+	}
+	if gotAuth != "Bearer secret" {
+		t.Errorf("expected --location-trusted to keep Authorization across hosts, got %q", gotAuth)
+	}
+}
+
+func TestDo_MaxRedirectsStopsFollowingAfterTheCap(t *testing.T) {
+	var srv *httptest.Server
+	hops := 0
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hops++
+		http.Redirect(w, r, srv.URL+"/", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL+"/")
+	_, err := New(Options{MaxRedirects: 2}).Do(req)
+	if err == nil {
+		t.Fatal("expected an error once --max-redirects is exceeded")
+	}
+}
+
+func TestDo_WarnsOnHTTPSToHTTPDowngrade(t *testing.T) {
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "ok")
+	}))
+	defer plain.Close()
+
+	secure := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, plain.URL+"/", http.StatusFound)
+	}))
+	defer secure.Close()
+
+	req := newTestRequest(t, secure.URL+"/")
+	c := New(Options{})
+	trustTestServer(t, c, secure)
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected the redirect to be followed, got body %q", resp.Body)
+	}
+	if len(resp.RedirectWarnings) == 0 {
+		t.Fatal("expected a redirect warning for the https->http downgrade")
+	}
+}
+
+func TestDo_NoRedirectToAllowsSameHostRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/end", http.StatusFound)
+			return
+		}
+		fmt.Fprint(w, "ok")
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL+"/start")
+	resp, err := New(Options{DisallowedRedirectHosts: []string{"evil.example"}}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "ok" {
+		t.Errorf("expected the same-host redirect to be followed, got body %q", resp.Body)
+	}
+}
+
+func TestDo_NoRedirectToBlocksDisallowedHost(t *testing.T) {
+	external := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "leaked")
+	}))
+	defer external.Close()
+	externalHost, err := url.Parse(external.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, external.URL+"/", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL+"/")
+	_, err = New(Options{DisallowedRedirectHosts: []string{externalHost.Hostname()}}).Do(req)
+	if err == nil {
+		t.Fatal("expected --no-redirect-to to abort the redirect to the disallowed host")
+	}
+}
+
+func TestDo_FinalURLReflectsTwoRedirects(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/start":
+			http.Redirect(w, r, srv.URL+"/middle", http.StatusFound)
+		case "/middle":
+			http.Redirect(w, r, srv.URL+"/end", http.StatusFound)
+		default:
+			fmt.Fprint(w, "ok")
+		}
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL+"/start")
+	resp, err := New(Options{}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := srv.URL + "/end"; resp.FinalURL != want {
+		t.Errorf("expected FinalURL %q, got %q", want, resp.FinalURL)
+	}
+}
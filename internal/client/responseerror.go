@@ -0,0 +1,81 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strconv"
+)
+
+// SchemeMismatchError reports that a request's scheme (http or https)
+// doesn't match what the server actually speaks, discovered from a
+// TLS-vs-plaintext protocol error. SuggestedScheme is the scheme that
+// would probably work instead; --auto-scheme retries once with it.
+type SchemeMismatchError struct {
+	Err             error
+	SuggestedScheme string
+}
+
+func (e *SchemeMismatchError) Error() string {
+	return fmt.Sprintf("%v (try --%s)", e.Err, e.SuggestedScheme)
+}
+
+func (e *SchemeMismatchError) Unwrap() error {
+	return e.Err
+}
+
+// responseHeadersTooLongPattern matches the error net/http.Transport
+// returns when a response's status line and headers together exceed
+// MaxResponseHeaderBytes, e.g. because a malformed or adversarial server
+// never terminates its status line.
+var responseHeadersTooLongPattern = regexp.MustCompile(`server response headers exceeded \d+ bytes`)
+
+// malformedResponsePattern extracts the raw bytes net/http quotes when a
+// server's reply doesn't start with a valid HTTP status line, e.g.
+// `net/http: HTTP/1.x transport connection broken: malformed HTTP response "..."`.
+var malformedResponsePattern = regexp.MustCompile(`malformed HTTP response "(.*)"$`)
+
+// tlsRecordContentTypes are the first-byte values of a TLS record
+// (RFC 8446 §5.1): change_cipher_spec, alert, handshake, and
+// application_data. A plaintext HTTP status line never starts with one
+// of these, since it starts with "HTTP/".
+var tlsRecordContentTypes = map[byte]bool{
+	20: true, // change_cipher_spec
+	21: true, // alert
+	22: true, // handshake
+	23: true, // application_data
+}
+
+// translateResponseError rewrites the connection-level errors
+// http.Client.Do returns for a response that never fully arrived into
+// something that says what actually happened, instead of a bare wrapped
+// EOF. net/http doesn't expose how many header bytes it managed to read
+// before the connection closed, so "closed after the status line" and
+// "closed mid-headers" aren't distinguishable from here; both are
+// reported as the connection closing while reading the response.
+func translateResponseError(err error) error {
+	if errors.Is(err, http.ErrSchemeMismatch) {
+		return &SchemeMismatchError{Err: err, SuggestedScheme: "http"}
+	}
+	if responseHeadersTooLongPattern.MatchString(err.Error()) {
+		return fmt.Errorf("response status line and headers too long (--max-response-header-size): %w", err)
+	}
+	if m := malformedResponsePattern.FindStringSubmatch(err.Error()); m != nil {
+		// net/http builds this message with %q, so the captured group is
+		// Go-quoted rather than the raw bytes it quoted; unquote it back
+		// before inspecting the first byte.
+		if raw, unquoteErr := strconv.Unquote(`"` + m[1] + `"`); unquoteErr == nil && len(raw) > 0 && tlsRecordContentTypes[raw[0]] {
+			return &SchemeMismatchError{Err: err, SuggestedScheme: "https"}
+		}
+		return err
+	}
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("server closed the connection before sending any response, which usually means the wrong port or scheme (e.g. a TLS server contacted over plain HTTP): %w", err)
+	}
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("server closed the connection while sending the response, before it was complete: %w", err)
+	}
+	return err
+}
@@ -0,0 +1,191 @@
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// acceptAndRespond starts a raw TCP listener that reads one request off
+// the connection (discarding it, up to and including its blank line)
+// and hands the connection to write for the caller to respond however
+// the test needs, then returns the listener's address.
+func acceptAndRespond(t *testing.T, write func(conn net.Conn)) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil || strings.TrimRight(line, "\r\n") == "" {
+				break
+			}
+		}
+		write(conn)
+	}()
+
+	return ln.Addr().String()
+}
+
+func TestDo_ConnectionClosedBeforeAnyResponseReportsWrongPortHint(t *testing.T) {
+	addr := acceptAndRespond(t, func(conn net.Conn) {
+		conn.Close()
+	})
+
+	_, err := New(Options{}).Do(newTestRequest(t, "http://"+addr+"/"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "before sending any response") {
+		t.Errorf("expected an error about no response being sent, got %q", err.Error())
+	}
+}
+
+func TestDo_ConnectionClosedMidHeadersReportsIncompleteResponse(t *testing.T) {
+	addr := acceptAndRespond(t, func(conn net.Conn) {
+		conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Type: text/pla"))
+		conn.Close()
+	})
+
+	_, err := New(Options{}).Do(newTestRequest(t, "http://"+addr+"/"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "before it was complete") {
+		t.Errorf("expected an error about an incomplete response, got %q", err.Error())
+	}
+}
+
+func TestDo_ConnectionClosedAfterStatusLineOnlyReportsIncompleteResponse(t *testing.T) {
+	addr := acceptAndRespond(t, func(conn net.Conn) {
+		conn.Write([]byte("HTTP/1.1 200 OK\r\n"))
+		conn.Close()
+	})
+
+	_, err := New(Options{}).Do(newTestRequest(t, "http://"+addr+"/"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "before it was complete") {
+		t.Errorf("expected an error about an incomplete response, got %q", err.Error())
+	}
+}
+
+func TestDo_TLSAlertOnPlaintextConnectionSuggestsHTTPSFlag(t *testing.T) {
+	addr := acceptAndRespond(t, func(conn net.Conn) {
+		// A TLS alert record: content type 21 (alert), version, length,
+		// followed by a couple of opaque payload bytes. A server
+		// listening for TLS that received a plaintext HTTP request would
+		// reply with something shaped like this instead of a status line.
+		conn.Write([]byte{0x15, 0x03, 0x03, 0x00, 0x02, 0x02, 0x28})
+		conn.Close()
+	})
+
+	_, err := New(Options{}).Do(newTestRequest(t, "http://"+addr+"/"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "--https") {
+		t.Errorf("expected the error to suggest --https, got %q", err.Error())
+	}
+	var mismatch *SchemeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatal("expected a *SchemeMismatchError")
+	}
+	if mismatch.SuggestedScheme != "https" {
+		t.Errorf("expected suggested scheme %q, got %q", "https", mismatch.SuggestedScheme)
+	}
+}
+
+// TestDo_HTTPSRequestToPlainServerSuggestsHTTPFlag pairs a plain
+// (non-TLS) httptest.Server with an https:// request, the reverse of
+// TestDo_TLSAlertOnPlaintextConnectionSuggestsHTTPSFlag, and checks the
+// resulting error names --http as the fix.
+func TestDo_HTTPSRequestToPlainServerSuggestsHTTPFlag(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	secureURL := "https://" + strings.TrimPrefix(srv.URL, "http://")
+	_, err := New(Options{}).Do(newTestRequest(t, secureURL))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "--http") {
+		t.Errorf("expected the error to suggest --http, got %q", err.Error())
+	}
+	var mismatch *SchemeMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatal("expected a *SchemeMismatchError")
+	}
+	if mismatch.SuggestedScheme != "http" {
+		t.Errorf("expected suggested scheme %q, got %q", "http", mismatch.SuggestedScheme)
+	}
+}
+
+// TestDo_PlaintextRequestToTLSServerGetsGracefulStatusFromServer pairs a
+// real TLS httptest.Server with a plain http:// request, the direction
+// TestDo_TLSAlertOnPlaintextConnectionSuggestsHTTPSFlag simulates by
+// hand: net/http.Server itself detects the plaintext request on a TLS
+// listener and answers with a normal, readable 400 response rather than
+// closing the connection or sending raw TLS bytes, so there's no client
+// error to translate here.
+func TestDo_PlaintextRequestToTLSServerGetsGracefulStatusFromServer(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	plainURL := strings.Replace(srv.URL, "https://", "http://", 1)
+	resp, err := New(Options{}).Do(newTestRequest(t, plainURL))
+	if err != nil {
+		t.Fatalf("expected net/http's own graceful handling, got error: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status %d, got %d", http.StatusBadRequest, resp.StatusCode)
+	}
+	if !strings.Contains(resp.Body, "HTTPS") {
+		t.Errorf("expected the body to explain the scheme mismatch, got %q", resp.Body)
+	}
+}
+
+// TestDo_MaxResponseHeaderSizeAbortsUnterminatedStatusLine sends a
+// megabyte of status-line bytes with no terminating "\n" at all,
+// simulating a malformed or adversarial server, and checks the client
+// gives up with a clear error instead of buffering without bound.
+func TestDo_MaxResponseHeaderSizeAbortsUnterminatedStatusLine(t *testing.T) {
+	addr := acceptAndRespond(t, func(conn net.Conn) {
+		conn.Write(bytes.Repeat([]byte("x"), 1<<20))
+		conn.Close()
+	})
+
+	_, err := New(Options{MaxResponseHeaderBytes: 8 << 10}).Do(newTestRequest(t, "http://"+addr+"/"))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if !strings.Contains(err.Error(), "--max-response-header-size") {
+		t.Errorf("expected the error to mention --max-response-header-size, got %q", err.Error())
+	}
+}
+
+func TestTranslateResponseError_LeavesUnrelatedErrorsUnchanged(t *testing.T) {
+	original := &net.OpError{Op: "dial", Err: net.UnknownNetworkError("bogus")}
+	if got := translateResponseError(original); got != original {
+		t.Errorf("expected an unrelated error to pass through unchanged, got %v", got)
+	}
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+)
+
+// TestNew_SNIOverridesTLSClientHelloServerName asserts --sni's ServerName
+// override reaches the wire, independent of the request's actual host.
+// The listener rejects the handshake as soon as it sees the
+// ClientHello, before any certificate is needed, since the test only
+// cares about what name the client offered.
+func TestNew_SNIOverridesTLSClientHelloServerName(t *testing.T) {
+	gotServerName := make(chan string, 1)
+	tlsConfig := &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			gotServerName <- hello.ServerName
+			return nil, errRejectHandshake
+		},
+	}
+	ln, err := tls.Listen("tcp", "127.0.0.1:0", tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		conn.(*tls.Conn).Handshake()
+	}()
+
+	req := newTestRequest(t, "https://"+ln.Addr().String()+"/")
+	c := New(Options{SNI: "override.example.com", Timeout: 2 * time.Second})
+
+	// The handshake is expected to fail (the test rejects it before ever
+	// presenting a certificate); only the ClientHello's SNI matters here.
+	c.Do(req)
+
+	select {
+	case name := <-gotServerName:
+		if name != "override.example.com" {
+			t.Errorf("expected ClientHello ServerName %q, got %q", "override.example.com", name)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for a TLS handshake attempt")
+	}
+}
+
+var errRejectHandshake = tlsRejectError("test intentionally rejects the handshake once ServerName is observed")
+
+type tlsRejectError string
+
+func (e tlsRejectError) Error() string { return string(e) }
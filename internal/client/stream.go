@@ -0,0 +1,57 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// streamChunkSize is the buffer size streamBody reads with. It's kept
+// small so a slow trickle of SSE events or chunked lines shows up on w
+// promptly instead of waiting for a large buffer to fill.
+const streamChunkSize = 512
+
+// writeStreamHeader writes resp's status line and headers to w, the
+// same shape send would otherwise only assemble after the whole body
+// has been read; Options.Stream needs them up front since the body is
+// about to be flushed to w as it arrives rather than all at once.
+func writeStreamHeader(w io.Writer, resp *http.Response) {
+	fmt.Fprintf(w, "%s %s\n", resp.Proto, resp.Status)
+	for _, name := range sortedHeaderNames(resp.Header) {
+		for _, value := range resp.Header[name] {
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+	fmt.Fprintln(w)
+}
+
+// streamBody copies r to w a chunk at a time, flushing w after each one
+// if it supports Flush, so a long-lived SSE or chunked response shows up
+// live instead of only after the connection closes. It still returns
+// every byte read, so the caller can build a normal HTTPResponse.Body
+// out of a streamed request the same as an unstreamed one.
+func streamBody(w io.Writer, r io.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	chunk := make([]byte, streamChunkSize)
+	for {
+		n, readErr := r.Read(chunk)
+		if n > 0 {
+			buf.Write(chunk[:n])
+			if _, err := w.Write(chunk[:n]); err != nil {
+				return buf.Bytes(), err
+			}
+			if f, ok := w.(interface{ Flush() error }); ok {
+				if err := f.Flush(); err != nil {
+					return buf.Bytes(), err
+				}
+			}
+		}
+		if readErr == io.EOF {
+			return buf.Bytes(), nil
+		}
+		if readErr != nil {
+			return buf.Bytes(), readErr
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a concurrency-safe io.Writer that timestamps every
+// Write, so a test can tell whether Options.Stream received several
+// separate writes spread over time rather than one write at the end.
+type syncBuffer struct {
+	mu     sync.Mutex
+	writes []string
+	times  []time.Time
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.writes = append(b.writes, string(p))
+	b.times = append(b.times, time.Now())
+	return len(p), nil
+}
+
+func (b *syncBuffer) snapshot() ([]string, []time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.writes...), append([]time.Time(nil), b.times...)
+}
+
+// TestDo_StreamFlushesEachSSEEventAsItArrives asserts Options.Stream
+// receives each server-sent event as soon as the testserver flushes it,
+// rather than only after the whole response has been read.
+func TestDo_StreamFlushesEachSSEEventAsItArrives(t *testing.T) {
+	events := []string{"data: one\n\n", "data: two\n\n", "data: three\n\n"}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		flusher := w.(http.Flusher)
+		for _, e := range events {
+			w.Write([]byte(e))
+			flusher.Flush()
+			time.Sleep(20 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	var out syncBuffer
+	req := newTestRequest(t, srv.URL)
+	c := New(Options{Stream: &out})
+
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, e := range events {
+		if !strings.Contains(resp.Body, e) {
+			t.Errorf("expected accumulated Body to contain %q, got %q", e, resp.Body)
+		}
+	}
+
+	writes, times := out.snapshot()
+	if len(writes) < len(events)+1 { // +1 for the status/header preamble
+		t.Fatalf("expected at least %d separate writes (header + each event), got %d: %v", len(events)+1, len(writes), writes)
+	}
+	if times[len(times)-1].Sub(times[0]) < 30*time.Millisecond {
+		t.Errorf("expected writes spread out over the server's delays, got them within %s", times[len(times)-1].Sub(times[0]))
+	}
+}
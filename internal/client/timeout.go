@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// dialContextFunc matches http.Transport.DialContext's signature, so a
+// timeout wrapper can compose in front of either a plain net.Dialer or
+// another wrapper (e.g. the one --resolve installs).
+type dialContextFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// withConnectTimeout bounds dial to timeout, returning a
+// *connectTimeoutError instead of dial's own error when it's the
+// wrapper's own deadline, not some outer context, that expired first.
+func withConnectTimeout(dial dialContextFunc, timeout time.Duration) dialContextFunc {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		conn, err := dial(ctx, network, addr)
+		if err != nil && ctx.Err() == context.DeadlineExceeded {
+			return nil, &connectTimeoutError{addr: addr, timeout: timeout}
+		}
+		return conn, err
+	}
+}
+
+// connectTimeoutError reports that TimeoutConnect elapsed while dialing
+// addr, so callers (and Do's error message) can tell a slow connect
+// apart from a slow read without inspecting net.Dialer internals.
+type connectTimeoutError struct {
+	addr    string
+	timeout time.Duration
+}
+
+func (e *connectTimeoutError) Error() string {
+	return fmt.Sprintf("connect timeout after %s to %s", e.timeout, e.addr)
+}
+
+// isTimeout reports whether err is a net.Error that timed out, the
+// shape net/http produces for both TLS handshake and read/write
+// deadlines exceeding http.Client.Timeout.
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithConnectTimeout_ReturnsConnectTimeoutErrorOnceItsOwnDeadlineExpires(t *testing.T) {
+	// A dial that hangs until its context is done, standing in for a
+	// dial to a blackholed address, which never resolves and never
+	// errors on its own within the test's lifetime.
+	hangingDial := func(ctx context.Context, network, addr string) (net.Conn, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	dial := withConnectTimeout(hangingDial, 10*time.Millisecond)
+	_, err := dial(context.Background(), "tcp", "unreachable.example:443")
+
+	var connErr *connectTimeoutError
+	if !errors.As(err, &connErr) {
+		t.Fatalf("expected a *connectTimeoutError, got %v", err)
+	}
+	if !strings.Contains(connErr.Error(), "unreachable.example:443") {
+		t.Errorf("expected the error to name the address, got %v", connErr)
+	}
+}
+
+func TestWithConnectTimeout_LeavesADialErrorAloneWhenItsOwnDeadlineDidntExpire(t *testing.T) {
+	refused := errors.New("connection refused")
+	dial := withConnectTimeout(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, refused
+	}, time.Second)
+
+	_, err := dial(context.Background(), "tcp", "example.com:443")
+	if !errors.Is(err, refused) {
+		t.Errorf("expected the underlying dial error to pass through unchanged, got %v", err)
+	}
+}
+
+func TestDo_TimeoutFailsOnSlowResponseAsReadTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte("too slow"))
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	c := New(Options{Timeout: 50 * time.Millisecond, TimeoutConnect: 50 * time.Millisecond})
+
+	_, err := c.Do(req)
+	if err == nil {
+		t.Fatal("expected an error from a response slower than --timeout")
+	}
+	if !strings.Contains(err.Error(), "read timeout after") {
+		t.Errorf("expected a read timeout error distinct from connect, got %v", err)
+	}
+}
+
+func TestConnectTimeoutError_NamesAddrAndDuration(t *testing.T) {
+	err := &connectTimeoutError{addr: "example.com:443", timeout: 5 * time.Second}
+	if got, want := err.Error(), "connect timeout after 5s to example.com:443"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// redactedHeaders names headers whose values are hidden from a trace by
+// default, since they typically carry credentials.
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+}
+
+const redactedValue = "[redacted]"
+
+// writeTraceRequest prints the outgoing request line, headers, and body
+// to w, each line prefixed with "> ", curl -v style.
+func writeTraceRequest(w io.Writer, httpReq *http.Request, body string, showSecrets bool) {
+	fmt.Fprintf(w, "> %s %s %s\n", httpReq.Method, httpReq.URL.RequestURI(), httpReq.Proto)
+	for _, name := range sortedHeaderNames(httpReq.Header) {
+		for _, value := range httpReq.Header[name] {
+			fmt.Fprintf(w, "> %s: %s\n", name, traceHeaderValue(name, value, showSecrets))
+		}
+	}
+	fmt.Fprintln(w, ">")
+	for _, line := range strings.Split(body, "\n") {
+		fmt.Fprintf(w, "> %s\n", line)
+	}
+}
+
+// writeTraceTLS prints the negotiated TLS version, cipher suite, and
+// peer certificate subject, if the connection used TLS. It's a no-op
+// for plain http.
+func writeTraceTLS(w io.Writer, state *tls.ConnectionState) {
+	if state == nil {
+		return
+	}
+	fmt.Fprintf(w, "* TLS version: %s\n", tls.VersionName(state.Version))
+	fmt.Fprintf(w, "* TLS cipher suite: %s\n", tls.CipherSuiteName(state.CipherSuite))
+	if len(state.PeerCertificates) > 0 {
+		fmt.Fprintf(w, "* peer certificate: %s\n", state.PeerCertificates[0].Subject)
+	}
+}
+
+// writeTraceResponse prints the response status line, headers, and body
+// to w, each line prefixed with "< ", after a leading "* effective URL:"
+// line naming the URL that actually produced resp (curl -v style),
+// which differs from the request line traced by writeTraceRequest when
+// redirects were followed.
+func writeTraceResponse(w io.Writer, resp *http.Response, body []byte, showSecrets bool) {
+	if resp.Request != nil {
+		fmt.Fprintf(w, "* effective URL: %s\n", resp.Request.URL)
+	}
+	fmt.Fprintf(w, "< %s %s\n", resp.Proto, resp.Status)
+	for _, name := range sortedHeaderNames(resp.Header) {
+		for _, value := range resp.Header[name] {
+			fmt.Fprintf(w, "< %s: %s\n", name, traceHeaderValue(name, value, showSecrets))
+		}
+	}
+	fmt.Fprintln(w, "<")
+	for _, line := range strings.Split(string(body), "\n") {
+		fmt.Fprintf(w, "< %s\n", line)
+	}
+}
+
+func traceHeaderValue(name, value string, showSecrets bool) string {
+	if !showSecrets && redactedHeaders[http.CanonicalHeaderKey(name)] {
+		return redactedValue
+	}
+	return value
+}
+
+func sortedHeaderNames(h http.Header) []string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
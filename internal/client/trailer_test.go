@@ -0,0 +1,60 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestDo_ChunkedResponseTrailerIsCaptured asserts a trailer header sent
+// after a chunked body's terminating chunk ends up in Trailer rather
+// than being left unread, which would otherwise corrupt a reused
+// connection's next response.
+func TestDo_ChunkedResponseTrailerIsCaptured(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Checksum")
+		flusher := w.(http.Flusher)
+		w.Write([]byte("hello"))
+		flusher.Flush()
+		w.Header().Set("X-Checksum", "deadbeef")
+	}))
+	defer srv.Close()
+
+	req := newTestRequest(t, srv.URL)
+	resp, err := New(Options{}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Body != "hello" {
+		t.Errorf("expected body %q, got %q", "hello", resp.Body)
+	}
+	if got := resp.Trailer.Get("X-Checksum"); got != "deadbeef" {
+		t.Errorf("expected trailer X-Checksum %q, got %q", "deadbeef", got)
+	}
+}
+
+// TestDo_TwoSequentialRequestsOnSameServerAfterTrailerAreUnaffected
+// guards against the trailer bytes being left unconsumed and corrupting
+// a later response parse: a second request to the same keep-alive
+// server must still parse cleanly.
+func TestDo_TwoSequentialRequestsOnSameServerAfterTrailerAreUnaffected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "X-Checksum")
+		flusher := w.(http.Flusher)
+		w.Write([]byte("first"))
+		flusher.Flush()
+		w.Header().Set("X-Checksum", "aaa111")
+	}))
+	defer srv.Close()
+
+	c := New(Options{})
+	for i, want := range []string{"first", "first"} {
+		resp, err := c.Do(newTestRequest(t, srv.URL))
+		if err != nil {
+			t.Fatalf("request %d: %v", i, err)
+		}
+		if resp.Body != want {
+			t.Errorf("request %d: expected body %q, got %q", i, want, resp.Body)
+		}
+	}
+}
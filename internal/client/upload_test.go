@@ -0,0 +1,70 @@
+package client
+
+import (
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestDo_StreamsBodyReaderFromFileWithoutBuffering uploads a temp file
+// well over a small buffer (32KB) via BodyReader/BodyLength and checks
+// the server received it byte-for-byte, exercising the lazy-streaming
+// path (--upload-file) rather than the buffered Body string.
+func TestDo_StreamsBodyReaderFromFileWithoutBuffering(t *testing.T) {
+	const size = 5 * 32 * 1024 // well over a 32KB buffer
+
+	want := make([]byte, size)
+	for i := range want {
+		want[i] = byte(i)
+	}
+	wantSum := sha256.Sum256(want)
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "upload.bin")
+	if err := os.WriteFile(path, want, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var gotLen int64
+	var gotSum [32]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := sha256.New()
+		n, err := io.Copy(h, r.Body)
+		if err != nil {
+			t.Errorf("reading uploaded body: %v", err)
+		}
+		gotLen = n
+		copy(gotSum[:], h.Sum(nil))
+	}))
+	defer srv.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := newTestRequest(t, srv.URL+"/")
+	req.Method = http.MethodPut
+	req.BodyReader = f
+	req.BodyLength = info.Size()
+
+	if _, err := New(Options{}).Do(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if gotLen != size {
+		t.Errorf("expected the server to receive %d bytes, got %d", size, gotLen)
+	}
+	if gotSum != wantSum {
+		t.Error("expected the uploaded file's contents to arrive intact")
+	}
+}
@@ -0,0 +1,177 @@
+// Package config loads default flag values and per-host overrides from
+// a small TOML-like config file, so users don't have to repeat the same
+// --insecure/--timeout/--proxy flags for every request against a given
+// host.
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Settings is one group of overridable defaults: either the file's
+// top-level defaults, or one [hosts."..."] section. A nil field means
+// "not set here"; Resolve fills it in from the next, less specific,
+// layer.
+type Settings struct {
+	Insecure *bool
+	Timeout  *time.Duration
+	Proxy    *string
+}
+
+// Config is a parsed config file: top-level defaults plus per-host
+// overrides, keyed by the exact host (host:port, matching req.URL.Host)
+// named in a [hosts."host"] section.
+type Config struct {
+	Defaults Settings
+	Hosts    map[string]Settings
+}
+
+// Resolved is the effective set of values for a specific host, with
+// every field defaulting to its built-in zero value if neither the
+// config's defaults nor a host section set it.
+type Resolved struct {
+	Insecure bool
+	Timeout  time.Duration
+	Proxy    string
+}
+
+// Resolve merges c's top-level defaults with the section for host, if
+// any, the host section taking precedence. A nil Config resolves to the
+// zero Resolved value, so callers don't need to special-case a missing
+// --config flag.
+func (c *Config) Resolve(host string) Resolved {
+	var r Resolved
+	if c == nil {
+		return r
+	}
+	apply := func(s Settings) {
+		if s.Insecure != nil {
+			r.Insecure = *s.Insecure
+		}
+		if s.Timeout != nil {
+			r.Timeout = *s.Timeout
+		}
+		if s.Proxy != nil {
+			r.Proxy = *s.Proxy
+		}
+	}
+	apply(c.Defaults)
+	apply(c.Hosts[host])
+	return r
+}
+
+// Load reads and parses the config file at path.
+func Load(path string) (*Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	defer f.Close()
+	return parse(f)
+}
+
+func parse(r io.Reader) (*Config, error) {
+	cfg := &Config{Hosts: make(map[string]Settings)}
+	section := "" // "" selects cfg.Defaults; otherwise a key into cfg.Hosts
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			host, err := parseSectionHeader(line)
+			if err != nil {
+				return nil, fmt.Errorf("config: line %d: %w", lineNo, err)
+			}
+			section = host
+			if _, ok := cfg.Hosts[section]; !ok {
+				cfg.Hosts[section] = Settings{}
+			}
+			continue
+		}
+
+		key, rawValue, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("config: line %d: malformed line %q, expected key = value", lineNo, line)
+		}
+		key = strings.TrimSpace(key)
+		rawValue = strings.TrimSpace(rawValue)
+
+		settings := cfg.Defaults
+		if section != "" {
+			settings = cfg.Hosts[section]
+		}
+		if err := applyKey(&settings, key, rawValue); err != nil {
+			return nil, fmt.Errorf("config: line %d: %w", lineNo, err)
+		}
+		if section == "" {
+			cfg.Defaults = settings
+		} else {
+			cfg.Hosts[section] = settings
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	return cfg, nil
+}
+
+// parseSectionHeader parses a `[hosts."name"]` line and returns "name".
+// The unquoted `[hosts.name]` form isn't supported since host names
+// contain dots, which would otherwise be ambiguous with TOML's
+// dotted-key section syntax.
+func parseSectionHeader(line string) (string, error) {
+	if !strings.HasSuffix(line, "]") {
+		return "", fmt.Errorf("malformed section header %q", line)
+	}
+	inner := line[1 : len(line)-1]
+	name, ok := strings.CutPrefix(inner, "hosts.")
+	if !ok {
+		return "", fmt.Errorf("unsupported section %q, expected [hosts.\"name\"]", line)
+	}
+	if len(name) < 2 || name[0] != '"' || name[len(name)-1] != '"' {
+		return "", fmt.Errorf("host section name must be quoted, got %q", line)
+	}
+	return name[1 : len(name)-1], nil
+}
+
+func applyKey(s *Settings, key, rawValue string) error {
+	switch key {
+	case "insecure":
+		v, err := strconv.ParseBool(rawValue)
+		if err != nil {
+			return fmt.Errorf("invalid insecure value %q: %w", rawValue, err)
+		}
+		s.Insecure = &v
+	case "timeout":
+		v, err := time.ParseDuration(unquote(rawValue))
+		if err != nil {
+			return fmt.Errorf("invalid timeout value %q: %w", rawValue, err)
+		}
+		s.Timeout = &v
+	case "proxy":
+		v := unquote(rawValue)
+		s.Proxy = &v
+	default:
+		return fmt.Errorf("unknown config key %q", key)
+	}
+	return nil
+}
+
+func unquote(s string) string {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
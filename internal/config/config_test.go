@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse_DefaultsAndHostOverridePrecedence(t *testing.T) {
+	input := `
+insecure = false
+timeout = "30s"
+proxy = "http://default-proxy:8080"
+
+[hosts."api.example.com"]
+insecure = true
+proxy = "http://special-proxy:9000"
+`
+	cfg, err := parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	def := cfg.Resolve("other.example.com")
+	if def.Insecure {
+		t.Error("expected default insecure=false for an unmatched host")
+	}
+	if def.Timeout != 30*time.Second {
+		t.Errorf("expected default timeout 30s, got %s", def.Timeout)
+	}
+	if def.Proxy != "http://default-proxy:8080" {
+		t.Errorf("expected default proxy, got %q", def.Proxy)
+	}
+
+	host := cfg.Resolve("api.example.com")
+	if !host.Insecure {
+		t.Error("expected host override insecure=true")
+	}
+	if host.Proxy != "http://special-proxy:9000" {
+		t.Errorf("expected host override proxy, got %q", host.Proxy)
+	}
+	if host.Timeout != 30*time.Second {
+		t.Errorf("expected timeout to fall back to default, got %s", host.Timeout)
+	}
+}
+
+func TestResolve_NilConfigReturnsZeroValue(t *testing.T) {
+	var cfg *Config
+	got := cfg.Resolve("example.com")
+	if got != (Resolved{}) {
+		t.Errorf("expected zero Resolved for a nil Config, got %+v", got)
+	}
+}
+
+func TestParse_UnknownKeyIsAnError(t *testing.T) {
+	if _, err := parse(strings.NewReader("bogus = 1\n")); err == nil {
+		t.Fatal("expected an error for an unknown config key")
+	}
+}
+
+func TestParse_MalformedSectionHeaderIsAnError(t *testing.T) {
+	if _, err := parse(strings.NewReader("[hosts.example.com]\n")); err == nil {
+		t.Fatal("expected an error for an unquoted host section name")
+	}
+}
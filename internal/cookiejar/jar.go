@@ -0,0 +1,124 @@
+// Package cookiejar implements a small, file-persisted cookie jar for
+// the --cookie-jar flag, so cookies from one invocation of the CLI can
+// be carried into the next.
+package cookiejar
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Jar holds cookies collected across one or more requests and knows how
+// to persist itself to disk as JSON.
+type Jar struct {
+	Cookies []*http.Cookie `json:"cookies"`
+}
+
+// Load reads the jar at path. A missing file yields an empty jar rather
+// than an error, since the first run of the tool won't have one yet.
+func Load(path string) (*Jar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Jar{}, nil
+		}
+		return nil, err
+	}
+	var j Jar
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// Save writes j to path as indented JSON.
+func (j *Jar) Save(path string) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Merge folds newCookies, as received from u, into the jar: matching
+// cookies (by name, domain, path) are replaced and expired cookies are
+// dropped.
+func (j *Jar) Merge(u *url.URL, newCookies []*http.Cookie) {
+	for _, c := range newCookies {
+		if c.Domain == "" {
+			c.Domain = u.Hostname()
+		}
+		if c.Path == "" {
+			c.Path = "/"
+		}
+		j.remove(c.Name, c.Domain, c.Path)
+		if !isExpired(c) {
+			j.Cookies = append(j.Cookies, c)
+		}
+	}
+}
+
+// For returns the cookies in the jar applicable to u: matching domain
+// and path, and not expired.
+func (j *Jar) For(u *url.URL) []*http.Cookie {
+	var matched []*http.Cookie
+	for _, c := range j.Cookies {
+		if isExpired(c) {
+			continue
+		}
+		if !domainMatches(u.Hostname(), c.Domain) {
+			continue
+		}
+		if !pathMatches(u.Path, c.Path) {
+			continue
+		}
+		if c.Secure && u.Scheme != "https" {
+			continue
+		}
+		matched = append(matched, c)
+	}
+	return matched
+}
+
+func (j *Jar) remove(name, domain, path string) {
+	kept := j.Cookies[:0]
+	for _, c := range j.Cookies {
+		if c.Name == name && c.Domain == domain && c.Path == path {
+			continue
+		}
+		kept = append(kept, c)
+	}
+	j.Cookies = kept
+}
+
+func isExpired(c *http.Cookie) bool {
+	return !c.Expires.IsZero() && c.Expires.Before(time.Now())
+}
+
+func domainMatches(host, domain string) bool {
+	domain = strings.TrimPrefix(domain, ".")
+	host = strings.ToLower(host)
+	domain = strings.ToLower(domain)
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// pathMatches implements RFC 6265 §5.1.4: reqPath matches cookiePath if
+// they're equal, or reqPath extends cookiePath at a "/" boundary. A bare
+// prefix check would wrongly match a cookie scoped to "/foo" against a
+// request for "/foobar".
+func pathMatches(reqPath, cookiePath string) bool {
+	if cookiePath == "" || cookiePath == "/" {
+		return true
+	}
+	if !strings.HasPrefix(reqPath, cookiePath) {
+		return false
+	}
+	if len(reqPath) == len(cookiePath) {
+		return true
+	}
+	return strings.HasSuffix(cookiePath, "/") || reqPath[len(cookiePath)] == '/'
+}
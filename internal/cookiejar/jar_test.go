@@ -0,0 +1,114 @@
+package cookiejar
+
+import (
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestJar_MergeAndFor(t *testing.T) {
+	u, err := url.Parse("https://example.com/app/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := &Jar{}
+	j.Merge(u, []*http.Cookie{
+		{Name: "session", Value: "abc123"},
+		{Name: "theme", Value: "dark", Domain: "example.com", Path: "/app"},
+	})
+
+	got := j.For(u)
+	if len(got) != 2 {
+		t.Fatalf("expected 2 cookies, got %d", len(got))
+	}
+}
+
+func TestJar_ForExcludesExpiredAndMismatchedDomain(t *testing.T) {
+	u, err := url.Parse("https://example.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := &Jar{Cookies: []*http.Cookie{
+		{Name: "old", Value: "x", Domain: "example.com", Path: "/", Expires: time.Now().Add(-time.Hour)},
+		{Name: "other", Value: "y", Domain: "other.com", Path: "/"},
+		{Name: "fresh", Value: "z", Domain: "example.com", Path: "/"},
+	}}
+
+	got := j.For(u)
+	if len(got) != 1 || got[0].Name != "fresh" {
+		t.Fatalf("expected only 'fresh' cookie, got %v", got)
+	}
+}
+
+func TestJar_ForExcludesCookieScopedToAnAdjacentPath(t *testing.T) {
+	u, err := url.Parse("https://example.com/foobar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	j := &Jar{Cookies: []*http.Cookie{
+		{Name: "scoped", Value: "x", Domain: "example.com", Path: "/foo"},
+	}}
+
+	got := j.For(u)
+	if len(got) != 0 {
+		t.Fatalf("expected no cookies for /foobar from a /foo-scoped cookie, got %v", got)
+	}
+}
+
+func TestPathMatches(t *testing.T) {
+	tests := []struct {
+		reqPath, cookiePath string
+		want                bool
+	}{
+		{"/foo", "/foo", true},
+		{"/foo/", "/foo", true},
+		{"/foo/bar", "/foo", true},
+		{"/foobar", "/foo", false},
+		{"/foobaz", "/foo", false},
+		{"/foo", "/foo/", false},
+		{"/foobar", "/foo/", false},
+		{"/anything", "/", true},
+		{"/anything", "", true},
+	}
+	for _, tt := range tests {
+		if got := pathMatches(tt.reqPath, tt.cookiePath); got != tt.want {
+			t.Errorf("pathMatches(%q, %q) = %v, want %v", tt.reqPath, tt.cookiePath, got, tt.want)
+		}
+	}
+}
+
+func TestJar_SaveAndLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "cookies.json")
+
+	u, _ := url.Parse("https://example.com/")
+	j := &Jar{}
+	j.Merge(u, []*http.Cookie{{Name: "a", Value: "1", Domain: "example.com", Path: "/"}})
+
+	if err := j.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(loaded.Cookies) != 1 || loaded.Cookies[0].Value != "1" {
+		t.Fatalf("unexpected loaded cookies: %v", loaded.Cookies)
+	}
+}
+
+func TestLoad_MissingFileReturnsEmptyJar(t *testing.T) {
+	j, err := Load(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(j.Cookies) != 0 {
+		t.Fatalf("expected empty jar, got %v", j.Cookies)
+	}
+}
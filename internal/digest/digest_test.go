@@ -0,0 +1,93 @@
+package digest
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"strings"
+	"testing"
+)
+
+func TestParseChallenge_ExtractsFields(t *testing.T) {
+	c, err := ParseChallenge(`Digest realm="test@example.com", qop="auth", nonce="abc123", opaque="xyz", algorithm=SHA-256`)
+	if err != nil {
+		t.Fatalf("ParseChallenge: %v", err)
+	}
+	if c.Realm != "test@example.com" || c.Nonce != "abc123" || c.Opaque != "xyz" || c.QOP != "auth" || c.Algorithm != "SHA-256" {
+		t.Fatalf("unexpected challenge: %+v", c)
+	}
+}
+
+func TestParseChallenge_DefaultsAlgorithmToMD5(t *testing.T) {
+	c, err := ParseChallenge(`Digest realm="r", nonce="n"`)
+	if err != nil {
+		t.Fatalf("ParseChallenge: %v", err)
+	}
+	if c.Algorithm != "MD5" {
+		t.Fatalf("expected MD5 default, got %q", c.Algorithm)
+	}
+}
+
+func TestParseChallenge_RejectsNonDigestScheme(t *testing.T) {
+	if _, err := ParseChallenge(`Basic realm="r"`); err == nil {
+		t.Fatal("expected an error for a non-Digest scheme")
+	}
+}
+
+func TestParseChallenge_RejectsMissingNonce(t *testing.T) {
+	if _, err := ParseChallenge(`Digest realm="r"`); err == nil {
+		t.Fatal("expected an error for a challenge missing a nonce")
+	}
+}
+
+func TestAuthorization_ComputesResponseMatchingRFC7616WithQOP(t *testing.T) {
+	challenge := Challenge{Realm: "r", Nonce: "n", QOP: "auth", Algorithm: "MD5"}
+	creds := Credentials{Username: "u", Password: "p"}
+
+	header, err := Authorization(challenge, creds, "GET", "/secret", 1)
+	if err != nil {
+		t.Fatalf("Authorization: %v", err)
+	}
+	values := parseParams(strings.TrimPrefix(header, "Digest "))
+
+	if values["nc"] != "00000001" {
+		t.Errorf("nc = %q, want 00000001", values["nc"])
+	}
+	if values["cnonce"] == "" {
+		t.Error("expected a non-empty cnonce")
+	}
+
+	ha1 := hexHash(md5.New, "u:r:p")
+	ha2 := hexHash(md5.New, "GET:/secret")
+	want := hexHash(md5.New, strings.Join([]string{ha1, "n", values["nc"], values["cnonce"], "auth", ha2}, ":"))
+	if values["response"] != want {
+		t.Errorf("response = %q, want %q", values["response"], want)
+	}
+}
+
+func TestAuthorization_ComputesResponseMatchingRFC7616WithoutQOP(t *testing.T) {
+	challenge := Challenge{Realm: "r", Nonce: "n", Algorithm: "SHA-256"}
+	creds := Credentials{Username: "u", Password: "p"}
+
+	header, err := Authorization(challenge, creds, "GET", "/secret", 1)
+	if err != nil {
+		t.Fatalf("Authorization: %v", err)
+	}
+	values := parseParams(strings.TrimPrefix(header, "Digest "))
+	if _, hasQOP := values["qop"]; hasQOP {
+		t.Error("expected no qop param when the challenge didn't offer one")
+	}
+
+	ha1 := hexHash(sha256.New, "u:r:p")
+	ha2 := hexHash(sha256.New, "GET:/secret")
+	want := hexHash(sha256.New, strings.Join([]string{ha1, "n", ha2}, ":"))
+	if values["response"] != want {
+		t.Errorf("response = %q, want %q", values["response"], want)
+	}
+}
+
+func TestAuthorization_RejectsUnsupportedAlgorithm(t *testing.T) {
+	challenge := Challenge{Realm: "r", Nonce: "n", Algorithm: "SHA-512"}
+	if _, err := Authorization(challenge, Credentials{Username: "u", Password: "p"}, "GET", "/", 1); err == nil {
+		t.Fatal("expected an error for an unsupported algorithm")
+	}
+}
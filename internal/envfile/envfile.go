@@ -0,0 +1,37 @@
+// Package envfile loads a "http-client.env.json" file (the VS Code REST
+// Client convention): a JSON object mapping environment names to a flat
+// map of variables, so a team can keep a dev/staging/prod variable set
+// alongside its .http files and pick one with --environment.
+package envfile
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// File maps an environment name (e.g. "dev", "prod") to its variables.
+type File map[string]map[string]string
+
+// Load reads and parses the env file at path.
+func Load(path string) (File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("envfile: %w", err)
+	}
+	var f File
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("envfile: parsing %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// Profile returns the variables for the named environment, erroring
+// clearly if the file has no such environment.
+func (f File) Profile(name string) (map[string]string, error) {
+	vars, ok := f[name]
+	if !ok {
+		return nil, fmt.Errorf("envfile: no environment named %q", name)
+	}
+	return vars, nil
+}
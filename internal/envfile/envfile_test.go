@@ -0,0 +1,57 @@
+package envfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeEnvFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "http-client.env.json")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_TwoProfilesResolveTheSameVariableDifferently(t *testing.T) {
+	path := writeEnvFile(t, `{
+		"dev": {"host": "dev.example.com"},
+		"prod": {"host": "example.com"}
+	}`)
+
+	f, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dev, err := f.Profile("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev["host"] != "dev.example.com" {
+		t.Errorf("dev host = %q, want %q", dev["host"], "dev.example.com")
+	}
+
+	prod, err := f.Profile("prod")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prod["host"] != "example.com" {
+		t.Errorf("prod host = %q, want %q", prod["host"], "example.com")
+	}
+}
+
+func TestFile_ProfileUnknownNameErrors(t *testing.T) {
+	f := File{"dev": {"host": "dev.example.com"}}
+	if _, err := f.Profile("staging"); err == nil {
+		t.Fatal("expected an error for an unknown environment name")
+	}
+}
+
+func TestLoad_MissingFileErrors(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected an error for a missing env file")
+	}
+}
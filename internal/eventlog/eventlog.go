@@ -0,0 +1,55 @@
+// Package eventlog implements a small, streamed JSON-lines logger for
+// --log-file: a machine-readable counterpart to --verbose's
+// curl -v style human output, meant for scripts and CI that want to
+// keep stdout limited to the response while still capturing connection,
+// timing, and redirect events somewhere.
+package eventlog
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event is one line written to a --log-file log. Fields irrelevant to
+// a given Type are left zero and omitted from the JSON.
+type Event struct {
+	Type       string    `json:"type"`
+	Time       time.Time `json:"time"`
+	Method     string    `json:"method,omitempty"`
+	URL        string    `json:"url,omitempty"`
+	StatusCode int       `json:"status_code,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty"`
+	Message    string    `json:"message,omitempty"`
+}
+
+// Logger writes Events as JSON lines to an underlying writer. It's
+// safe for concurrent use, since --parallel sends from multiple
+// goroutines.
+type Logger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// New returns a Logger writing to w.
+func New(w io.Writer) *Logger {
+	return &Logger{w: w}
+}
+
+// Log writes e as a single JSON line, filling in Time if it's zero.
+func (l *Logger) Log(e Event) error {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err = l.w.Write(data)
+	return err
+}
@@ -0,0 +1,42 @@
+package eventlog
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLogger_LogWritesOneJSONLinePerEvent(t *testing.T) {
+	var buf bytes.Buffer
+	l := New(&buf)
+
+	if err := l.Log(Event{Type: "request", Method: "GET", URL: "https://example.com/"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log(Event{Type: "response", StatusCode: 200, DurationMS: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	var first, second Event
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatal(err)
+	}
+	if first.Type != "request" || first.URL != "https://example.com/" {
+		t.Errorf("unexpected first event: %+v", first)
+	}
+	if second.Type != "response" || second.StatusCode != 200 || second.DurationMS != 42 {
+		t.Errorf("unexpected second event: %+v", second)
+	}
+	if first.Time.IsZero() {
+		t.Error("expected Time to be filled in automatically")
+	}
+}
@@ -0,0 +1,131 @@
+package export
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// Exchange is a single recorded request/response pair, written by
+// --record and read back by --replay to reproduce an exchange offline.
+// Unlike HAR (see har.go), which stores a response body as a JSON
+// string and so can mangle non-UTF-8 bytes, Exchange base64-encodes the
+// raw body, so a binary response replays byte for byte.
+type Exchange struct {
+	Request  ExchangeRequest  `json:"request"`
+	Response ExchangeResponse `json:"response"`
+}
+
+// ExchangeRequest is Exchange's "request" object, enough to reconstruct
+// what was sent for display; --replay never re-sends it.
+type ExchangeRequest struct {
+	Method  string   `json:"method"`
+	URL     string   `json:"url"`
+	Proto   string   `json:"proto"`
+	Headers []Header `json:"headers"`
+	Body    string   `json:"body,omitempty"`
+}
+
+// ExchangeResponse is Exchange's "response" object. RawBody is
+// base64-encoded so it round-trips exactly, including binary content;
+// Body is the same text HTTPResponse.Body would hold (post charset
+// decoding), kept separately since the two can differ for a non-UTF-8
+// charset.
+type ExchangeResponse struct {
+	Proto      string        `json:"proto"`
+	StatusCode int           `json:"statusCode"`
+	Status     string        `json:"status"`
+	Headers    []Header      `json:"headers"`
+	Body       string        `json:"body"`
+	RawBody    string        `json:"rawBody"`
+	Timing     time.Duration `json:"timingNanoseconds"`
+}
+
+// NewExchange builds an Exchange from req/resp, ready to be written with
+// Write.
+func NewExchange(req *models.Request, resp *models.HTTPResponse) Exchange {
+	return Exchange{
+		Request: ExchangeRequest{
+			Method:  req.Method,
+			URL:     req.URL.String(),
+			Proto:   req.Proto,
+			Headers: headers(req.Header),
+			Body:    req.Body,
+		},
+		Response: ExchangeResponse{
+			Proto:      resp.Proto,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			Headers:    headers(resp.Header),
+			Body:       resp.Body,
+			RawBody:    base64.StdEncoding.EncodeToString(resp.RawBody),
+			Timing:     resp.Timing,
+		},
+	}
+}
+
+// Write encodes e as indented JSON to w.
+func (e Exchange) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// ReadExchange decodes an Exchange previously written by Write.
+func ReadExchange(r io.Reader) (Exchange, error) {
+	var e Exchange
+	if err := json.NewDecoder(r).Decode(&e); err != nil {
+		return Exchange{}, fmt.Errorf("export: decode exchange: %w", err)
+	}
+	return e, nil
+}
+
+// ToRequest reconstructs the recorded request, for --replay to print
+// alongside its response the same way a live send would.
+func (e Exchange) ToRequest() (*models.Request, error) {
+	u, err := url.Parse(e.Request.URL)
+	if err != nil {
+		return nil, fmt.Errorf("export: replayed request URL %q: %w", e.Request.URL, err)
+	}
+	req := models.New()
+	req.Method = e.Request.Method
+	req.URL = u
+	req.Proto = e.Request.Proto
+	req.Header = headerMap(e.Request.Headers)
+	req.Body = e.Request.Body
+	return req, nil
+}
+
+// ToResponse reconstructs the recorded response, for --replay to print
+// without ever touching the network.
+func (e Exchange) ToResponse() (*models.HTTPResponse, error) {
+	rawBody, err := base64.StdEncoding.DecodeString(e.Response.RawBody)
+	if err != nil {
+		return nil, fmt.Errorf("export: replayed response body: %w", err)
+	}
+	return &models.HTTPResponse{
+		Proto:      e.Response.Proto,
+		StatusCode: e.Response.StatusCode,
+		Status:     e.Response.Status,
+		Header:     headerMap(e.Response.Headers),
+		Body:       e.Response.Body,
+		RawBody:    rawBody,
+		Timing:     e.Response.Timing,
+	}, nil
+}
+
+// headerMap rebuilds an http.Header from Header's flat name/value list,
+// the inverse of headers.
+func headerMap(hs []Header) http.Header {
+	h := make(http.Header, len(hs))
+	for _, entry := range hs {
+		h.Add(entry.Name, entry.Value)
+	}
+	return h
+}
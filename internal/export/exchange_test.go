@@ -0,0 +1,66 @@
+package export
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+func TestExchange_RoundTripsThroughJSON(t *testing.T) {
+	req := models.New()
+	req.Method = http.MethodPost
+	req.URL, _ = url.Parse("https://example.com/widgets?limit=10")
+	req.Proto = "HTTP/1.1"
+	req.Header.Set("Content-Type", "application/json")
+	req.Body = `{"name":"widget"}`
+
+	rawBody := []byte{0x00, 0x01, 0xff, 'h', 'i'}
+	resp := &models.HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 201,
+		Status:     "201 Created",
+		Header:     http.Header{"Content-Type": {"application/octet-stream"}},
+		Body:       string(rawBody),
+		RawBody:    rawBody,
+		Timing:     150 * time.Millisecond,
+	}
+
+	var buf strings.Builder
+	if err := NewExchange(req, resp).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	exchange, err := ReadExchange(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	gotReq, err := exchange.ToRequest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotReq.Method != req.Method || gotReq.URL.String() != req.URL.String() || gotReq.Body != req.Body {
+		t.Errorf("request didn't round-trip: got %+v", gotReq)
+	}
+	if gotReq.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected request Content-Type to round-trip, got %q", gotReq.Header.Get("Content-Type"))
+	}
+
+	gotResp, err := exchange.ToResponse()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotResp.StatusCode != resp.StatusCode || gotResp.Status != resp.Status {
+		t.Errorf("response status didn't round-trip: got %+v", gotResp)
+	}
+	if string(gotResp.RawBody) != string(rawBody) {
+		t.Errorf("expected raw body to round-trip byte for byte, got %v, want %v", gotResp.RawBody, rawBody)
+	}
+	if gotResp.Timing != resp.Timing {
+		t.Errorf("expected timing to round-trip, got %v, want %v", gotResp.Timing, resp.Timing)
+	}
+}
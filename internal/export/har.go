@@ -0,0 +1,258 @@
+// Package export builds HAR (HTTP Archive) 1.2 logs from sent requests
+// and responses, for --trace to write out so the exchange can be
+// attached to a bug report or opened in any HAR-aware tool. It also
+// defines Exchange, a lossless single-exchange format --record/--replay
+// use to reproduce a request/response pair offline (see exchange.go).
+package export
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+const harVersion = "1.2"
+
+// creatorName/creatorVersion identify this tool in the HAR log's
+// "creator" object; the CLI doesn't currently track a release version,
+// so Version is a placeholder.
+const (
+	creatorName    = "http"
+	creatorVersion = "dev"
+)
+
+// HAR is a HAR 1.2 log document.
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+// Log is the HAR document's top-level "log" object.
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+// Creator identifies the tool that produced the log.
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Entry is one request/response exchange.
+type Entry struct {
+	StartedDateTime string   `json:"startedDateTime"`
+	Time            float64  `json:"time"`
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+	Cache           struct{} `json:"cache"`
+	Timings         Timings  `json:"timings"`
+}
+
+// Request is an entry's "request" object.
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []Header    `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int64       `json:"headersSize"`
+	BodySize    int64       `json:"bodySize"`
+}
+
+// Response is an entry's "response" object.
+type Response struct {
+	Status      int      `json:"status"`
+	StatusText  string   `json:"statusText"`
+	HTTPVersion string   `json:"httpVersion"`
+	Cookies     []Cookie `json:"cookies"`
+	Headers     []Header `json:"headers"`
+	Content     Content  `json:"content"`
+	RedirectURL string   `json:"redirectURL"`
+	HeadersSize int64    `json:"headersSize"`
+	BodySize    int64    `json:"bodySize"`
+}
+
+// Content describes a response body.
+type Content struct {
+	Size     int64  `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+// PostData describes a request body.
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Header is a single HAR name/value header entry.
+type Header struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// NameValue is a single HAR name/value query-string entry.
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Cookie is a single HAR name/value cookie entry.
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Timings breaks a round trip into HAR's send/wait/receive phases, all
+// in milliseconds. This CLI only measures the whole round trip, so the
+// full duration is attributed to Wait and Send/Receive are left at 0.
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// New returns an empty HAR log ready to accumulate entries.
+func New() *HAR {
+	return &HAR{Log: Log{
+		Version: harVersion,
+		Creator: Creator{Name: creatorName, Version: creatorVersion},
+	}}
+}
+
+// Add appends entry to h's log.
+func (h *HAR) Add(entry Entry) {
+	h.Log.Entries = append(h.Log.Entries, entry)
+}
+
+// Write encodes h as indented JSON to w.
+func (h *HAR) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h)
+}
+
+// NewEntry builds a HAR entry from req/resp, using startedAt as the
+// entry's startedDateTime and resp.Timing as the whole round trip's
+// duration.
+func NewEntry(req *models.Request, resp *models.HTTPResponse, startedAt time.Time) Entry {
+	millis := float64(resp.Timing) / float64(time.Millisecond)
+	return Entry{
+		StartedDateTime: startedAt.UTC().Format(time.RFC3339Nano),
+		Time:            millis,
+		Request:         newRequest(req),
+		Response:        newResponse(resp),
+		Timings:         Timings{Wait: millis},
+	}
+}
+
+func newRequest(req *models.Request) Request {
+	r := Request{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     requestCookies(req.Header),
+		Headers:     headers(req.Header),
+		QueryString: queryString(req.URL),
+		HeadersSize: -1,
+		BodySize:    int64(len(req.Body)),
+	}
+	if req.Body != "" {
+		r.PostData = &PostData{MimeType: req.Header.Get("Content-Type"), Text: req.Body}
+	}
+	return r
+}
+
+func newResponse(resp *models.HTTPResponse) Response {
+	return Response{
+		Status:      resp.StatusCode,
+		StatusText:  statusText(resp.Status),
+		HTTPVersion: resp.Proto,
+		Cookies:     responseCookies(resp.Cookies),
+		Headers:     headers(resp.Header),
+		Content: Content{
+			Size:     int64(len(resp.Body)),
+			MimeType: resp.Header.Get("Content-Type"),
+			Text:     resp.Body,
+		},
+		HeadersSize: -1,
+		BodySize:    int64(len(resp.Body)),
+	}
+}
+
+// statusText strips the leading status code from an http.Response's
+// "200 OK"-style Status string.
+func statusText(status string) string {
+	if _, text, ok := strings.Cut(status, " "); ok {
+		return text
+	}
+	return status
+}
+
+func headers(h http.Header) []Header {
+	var out []Header
+	for _, name := range sortedNames(h) {
+		for _, v := range h[name] {
+			out = append(out, Header{Name: name, Value: v})
+		}
+	}
+	return out
+}
+
+func queryString(u *url.URL) []NameValue {
+	q := u.Query()
+	names := make([]string, 0, len(q))
+	for name := range q {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out []NameValue
+	for _, name := range names {
+		for _, v := range q[name] {
+			out = append(out, NameValue{Name: name, Value: v})
+		}
+	}
+	return out
+}
+
+func requestCookies(h http.Header) []Cookie {
+	var out []Cookie
+	for _, line := range h.Values("Cookie") {
+		for _, part := range strings.Split(line, ";") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(part, "=")
+			out = append(out, Cookie{Name: name, Value: value})
+		}
+	}
+	return out
+}
+
+func responseCookies(cookies []*http.Cookie) []Cookie {
+	out := make([]Cookie, 0, len(cookies))
+	for _, c := range cookies {
+		out = append(out, Cookie{Name: c.Name, Value: c.Value})
+	}
+	return out
+}
+
+func sortedNames(h http.Header) []string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
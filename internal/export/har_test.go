@@ -0,0 +1,103 @@
+package export
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+func TestNewEntry_ValidatesAgainstHARSchema(t *testing.T) {
+	req := models.New()
+	req.Method = http.MethodPost
+	req.URL, _ = url.Parse("https://example.com/widgets?limit=10")
+	req.Proto = "HTTP/1.1"
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cookie", "session=abc123")
+	req.Body = `{"name":"widget"}`
+
+	resp := &models.HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 201,
+		Status:     "201 Created",
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       `{"id":1}`,
+		Timing:     150 * time.Millisecond,
+		Cookies:    []*http.Cookie{{Name: "tracking", Value: "xyz"}},
+	}
+
+	har := New()
+	har.Add(NewEntry(req, resp, time.Unix(0, 0)))
+
+	var buf strings.Builder
+	if err := har.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(buf.String()), &doc); err != nil {
+		t.Fatalf("emitted JSON did not parse: %v", err)
+	}
+
+	log, ok := doc["log"].(map[string]any)
+	if !ok {
+		t.Fatal("missing log object")
+	}
+	if log["version"] != "1.2" {
+		t.Errorf("expected log.version = 1.2, got %v", log["version"])
+	}
+	creator, ok := log["creator"].(map[string]any)
+	if !ok || creator["name"] == "" {
+		t.Errorf("expected log.creator.name to be set, got %v", log["creator"])
+	}
+
+	entries, ok := log["entries"].([]any)
+	if !ok || len(entries) != 1 {
+		t.Fatalf("expected exactly one entry, got %v", log["entries"])
+	}
+	entry := entries[0].(map[string]any)
+
+	if entry["startedDateTime"] == "" || entry["startedDateTime"] == nil {
+		t.Error("expected a non-empty startedDateTime")
+	}
+	if _, ok := entry["time"].(float64); !ok {
+		t.Errorf("expected entry.time to be a number, got %v", entry["time"])
+	}
+
+	entryReq, ok := entry["request"].(map[string]any)
+	if !ok {
+		t.Fatal("missing request object")
+	}
+	if entryReq["method"] != http.MethodPost {
+		t.Errorf("expected request.method = POST, got %v", entryReq["method"])
+	}
+	if entryReq["url"] != "https://example.com/widgets?limit=10" {
+		t.Errorf("expected request.url to round-trip, got %v", entryReq["url"])
+	}
+	if _, ok := entryReq["headers"].([]any); !ok {
+		t.Error("expected request.headers to be an array")
+	}
+	if _, ok := entryReq["queryString"].([]any); !ok {
+		t.Error("expected request.queryString to be an array")
+	}
+
+	entryResp, ok := entry["response"].(map[string]any)
+	if !ok {
+		t.Fatal("missing response object")
+	}
+	if entryResp["status"] != float64(201) {
+		t.Errorf("expected response.status = 201, got %v", entryResp["status"])
+	}
+	content, ok := entryResp["content"].(map[string]any)
+	if !ok || content["text"] != `{"id":1}` {
+		t.Errorf("expected response.content.text to carry the body, got %v", entryResp["content"])
+	}
+
+	if _, ok := entry["timings"].(map[string]any); !ok {
+		t.Error("expected a timings object")
+	}
+}
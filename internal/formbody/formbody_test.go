@@ -0,0 +1,76 @@
+package formbody
+
+import (
+	"mime"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestURLEncoded_EncodesReservedCharacters(t *testing.T) {
+	got := URLEncoded([]Field{{Name: "q", Value: "a b&c"}, {Name: "n", Value: "1"}})
+	if got != "n=1&q=a+b%26c" {
+		t.Errorf("unexpected body: %q", got)
+	}
+}
+
+func TestMultipart_RoundTripsFieldsAndFiles(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "upload.txt")
+	if err := os.WriteFile(path, []byte("file contents"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	body, contentType, err := Multipart(
+		[]Field{{Name: "name", Value: "gopher"}},
+		[]FileField{{Name: "avatar", Path: path}},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(contentType, "multipart/form-data; boundary=") {
+		t.Errorf("unexpected content type: %q", contentType)
+	}
+
+	r := multipart.NewReader(strings.NewReader(body), params["boundary"])
+	form, err := r.ReadForm(1 << 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer form.RemoveAll()
+
+	if got := form.Value["name"]; len(got) != 1 || got[0] != "gopher" {
+		t.Errorf("expected name field %q, got %v", "gopher", got)
+	}
+	files := form.File["avatar"]
+	if len(files) != 1 {
+		t.Fatalf("expected 1 file part, got %d", len(files))
+	}
+	if files[0].Filename != "upload.txt" {
+		t.Errorf("expected filename %q, got %q", "upload.txt", files[0].Filename)
+	}
+	f, err := files[0].Open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	buf := make([]byte, 64)
+	n, _ := f.Read(buf)
+	if string(buf[:n]) != "file contents" {
+		t.Errorf("expected file contents %q, got %q", "file contents", string(buf[:n]))
+	}
+}
+
+func TestMultipart_MissingFileReturnsError(t *testing.T) {
+	_, _, err := Multipart(nil, []FileField{{Name: "avatar", Path: filepath.Join(t.TempDir(), "missing.txt")}})
+	if err == nil {
+		t.Fatal("expected an error for a missing file")
+	}
+}
@@ -0,0 +1,381 @@
+// Package httpfile parses the ".http" request file format: one or more
+// requests separated by a line starting with "###", each made up of an
+// optional preamble of "@name = value" variable declarations, a request
+// line, headers and "@name = expr" response-capture directives, a blank
+// line, and an optional body. A line starting with "#" or "//" before
+// the blank line is a comment and is dropped, unless it's an
+// "@expect ..." directive (e.g. "# @expect status 200"), which is
+// parsed into a models.Expectation instead; once the body starts, "#"
+// and "//" are ordinary body bytes (e.g. a shell script or a JSON
+// string containing one) and are never touched.
+package httpfile
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// defaultMaxHeaderLines is used when Parse's maxHeaderLines argument is
+// 0, capping how many header/capture lines a single request's header
+// block can contain before Parse gives up on it as malformed.
+const defaultMaxHeaderLines = 1000
+
+// Parse reads r and returns every request it contains, in file order,
+// along with any non-fatal warnings (currently just obsolete header
+// folding; see appendFoldedLine). maxHeaderLines caps the number of
+// header/capture lines read per request, guarding against a malformed
+// or adversarial input being parsed forever; 0 uses defaultMaxHeaderLines.
+func Parse(r io.Reader, maxHeaderLines int) ([]*models.Request, []string, error) {
+	if maxHeaderLines <= 0 {
+		maxHeaderLines = defaultMaxHeaderLines
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		requests        []*models.Request
+		warnings        []string
+		cur             *models.Request
+		bodyLines       []string
+		inBody          bool
+		headerLineCount int
+	)
+
+	flush := func() {
+		if cur == nil {
+			return
+		}
+		cur.Body = strings.TrimRight(strings.Join(bodyLines, "\n"), "\n")
+		requests = append(requests, cur)
+		cur = nil
+		bodyLines = nil
+		inBody = false
+	}
+
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := scanner.Text()
+
+		trimmedLine := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmedLine, "###") {
+			flush()
+			cur = models.New()
+			cur.Name = strings.TrimSpace(strings.TrimPrefix(trimmedLine, "###"))
+			continue
+		}
+
+		if !inBody {
+			if directive, ok := expectDirectiveBody(trimmedLine); ok {
+				if cur == nil {
+					cur = models.New()
+				}
+				exp, err := parseExpectLine(directive)
+				if err != nil {
+					return nil, warnings, fmt.Errorf("httpfile: line %d: %w", lineNo, err)
+				}
+				cur.Expectations = append(cur.Expectations, exp)
+				continue
+			}
+			if strings.HasPrefix(trimmedLine, "#") || strings.HasPrefix(trimmedLine, "//") {
+				continue
+			}
+		}
+
+		if cur == nil {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			cur = models.New()
+		}
+
+		if cur.Method == "" {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if strings.HasPrefix(trimmedLine, "@") {
+				name, value, err := parseDeclarationLine(trimmedLine)
+				if err != nil {
+					return nil, warnings, fmt.Errorf("httpfile: line %d: %w", lineNo, err)
+				}
+				cur.Declarations = append(cur.Declarations, models.Declaration{Name: name, Value: value})
+				continue
+			}
+			method, target, proto, err := parseRequestLine(line)
+			if err != nil {
+				return nil, warnings, fmt.Errorf("httpfile: line %d: %w", lineNo, err)
+			}
+			cur.Method = method
+			cur.RawTarget = target
+			cur.Proto = proto
+			u, err := ParseTarget(method, target)
+			if err != nil {
+				return nil, warnings, fmt.Errorf("httpfile: line %d: invalid request target %q: %w", lineNo, target, err)
+			}
+			cur.URL = u
+			headerLineCount = 0
+			continue
+		}
+
+		if !inBody {
+			trimmed := strings.TrimSpace(line)
+			if trimmed == "" {
+				inBody = true
+				continue
+			}
+
+			headerLineCount++
+			if headerLineCount > maxHeaderLines {
+				return nil, warnings, fmt.Errorf("httpfile: line %d: exceeded --max-header-lines (%d) for %s %s", lineNo, maxHeaderLines, cur.Method, cur.RawTarget)
+			}
+
+			if isFoldedContinuation(line) && len(cur.RawHeaders) > 0 {
+				warnings = append(warnings, appendFoldedLine(cur, line, lineNo))
+				continue
+			}
+			if strings.HasPrefix(trimmed, "@") {
+				name, expr, err := parseCaptureLine(trimmed)
+				if err != nil {
+					return nil, warnings, fmt.Errorf("httpfile: line %d: %w", lineNo, err)
+				}
+				cur.Captures = append(cur.Captures, models.Capture{Name: name, Expr: expr})
+				continue
+			}
+			name, value, err := parseHeaderLine(line)
+			if err != nil {
+				return nil, warnings, fmt.Errorf("httpfile: line %d: %w", lineNo, err)
+			}
+			cur.Header.Add(name, value)
+			cur.RawHeaders = append(cur.RawHeaders, models.RawHeader{Name: name, Value: value})
+			continue
+		}
+
+		bodyLines = append(bodyLines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, warnings, err
+	}
+	flush()
+
+	return requests, warnings, nil
+}
+
+// Write serializes req back into the .http format Parse reads: a
+// request line, its headers, a blank line, and the body if any. It's
+// the counterpart Parse needs for round-tripping a request that was
+// built or modified in memory, e.g. --repl's "save" command.
+func Write(w io.Writer, req *models.Request) error {
+	target := req.RawTarget
+	if target == "" && req.URL != nil {
+		target = req.URL.RequestURI()
+	}
+	proto := req.Proto
+	if proto == "" {
+		proto = "HTTP/1.1"
+	}
+	if _, err := fmt.Fprintf(w, "%s %s %s\n", req.Method, target, proto); err != nil {
+		return err
+	}
+
+	for _, h := range headerLines(req) {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", h.Name, h.Value); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintln(w); err != nil {
+		return err
+	}
+	if req.Body != "" {
+		if _, err := fmt.Fprintln(w, req.Body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// headerLines returns req's headers in the order to write them:
+// RawHeaders' original casing and order when present, falling back to
+// Header's canonical names sorted for a stable, deterministic output.
+func headerLines(req *models.Request) []models.RawHeader {
+	if len(req.RawHeaders) > 0 {
+		return req.RawHeaders
+	}
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []models.RawHeader
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			lines = append(lines, models.RawHeader{Name: name, Value: value})
+		}
+	}
+	return lines
+}
+
+// ParseTarget parses a request-target for method. CONNECT's target is
+// just a "host:port" authority, which url.Parse would otherwise
+// misread as a "host:" scheme with an opaque "port" (RFC 3986's generic
+// scheme:opaque form); every other method, including OPTIONS's
+// asterisk-form ("*"), parses with the regular URL parser, which
+// already treats "*" as a valid relative path. Callers that re-resolve
+// a request's target after chain variable substitution (see
+// internal/cli) must use this instead of url.Parse directly, or a
+// resolved CONNECT target will be parsed incorrectly.
+func ParseTarget(method, target string) (*url.URL, error) {
+	if method != http.MethodConnect {
+		return url.Parse(target)
+	}
+	u, err := url.ParseRequestURI("http://" + target)
+	if err != nil {
+		return nil, err
+	}
+	u.Scheme = ""
+	return u, nil
+}
+
+func parseRequestLine(line string) (method, target, proto string, err error) {
+	fields := strings.Fields(line)
+	switch len(fields) {
+	case 2:
+		return fields[0], fields[1], "HTTP/1.1", nil
+	case 3:
+		return fields[0], fields[1], fields[2], nil
+	default:
+		return "", "", "", fmt.Errorf("malformed request line %q", line)
+	}
+}
+
+// expectDirectiveBody reports whether trimmedLine is a "# @expect ..."
+// or "// @expect ..." comment directive, returning the text after
+// "@expect" (with surrounding whitespace trimmed) if so.
+func expectDirectiveBody(trimmedLine string) (body string, ok bool) {
+	comment := strings.TrimPrefix(strings.TrimPrefix(trimmedLine, "//"), "#")
+	if comment == trimmedLine {
+		return "", false
+	}
+	comment = strings.TrimSpace(comment)
+	rest, ok := strings.CutPrefix(comment, "@expect")
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSpace(rest), true
+}
+
+// parseExpectLine parses the body of an "@expect ..." directive (with
+// "@expect" itself already stripped) into a models.Expectation. Valid
+// forms are:
+//
+//	status <code>
+//	header <Name>: <value>
+//	body-contains <substring>
+func parseExpectLine(body string) (models.Expectation, error) {
+	kind, rest, _ := strings.Cut(body, " ")
+	rest = strings.TrimSpace(rest)
+	switch kind {
+	case "status":
+		code, err := strconv.Atoi(rest)
+		if err != nil {
+			return models.Expectation{}, fmt.Errorf("malformed @expect status directive %q: %w", body, err)
+		}
+		return models.Expectation{Kind: models.ExpectStatus, Status: code}, nil
+	case "header":
+		name, value, ok := strings.Cut(rest, ":")
+		if !ok {
+			return models.Expectation{}, fmt.Errorf("malformed @expect header directive %q, expected header Name: value", body)
+		}
+		return models.Expectation{Kind: models.ExpectHeader, Header: strings.TrimSpace(name), Value: strings.TrimSpace(value)}, nil
+	case "body-contains":
+		if rest == "" {
+			return models.Expectation{}, fmt.Errorf("malformed @expect body-contains directive %q, expected a substring", body)
+		}
+		return models.Expectation{Kind: models.ExpectBodyContains, Value: rest}, nil
+	default:
+		return models.Expectation{}, fmt.Errorf("unrecognized @expect directive %q, expected status, header, or body-contains", body)
+	}
+}
+
+// parseCaptureLine parses a "@name = expr" response-capture directive,
+// trimmed is the line with leading/trailing whitespace already removed.
+func parseCaptureLine(trimmed string) (name, expr string, err error) {
+	body := strings.TrimPrefix(trimmed, "@")
+	name, expr, ok := strings.Cut(body, "=")
+	if !ok {
+		return "", "", fmt.Errorf("malformed capture directive %q, expected @name = expr", trimmed)
+	}
+	name = strings.TrimSpace(name)
+	expr = strings.TrimSpace(expr)
+	if name == "" {
+		return "", "", fmt.Errorf("empty capture name in %q", trimmed)
+	}
+	return name, expr, nil
+}
+
+// parseDeclarationLine parses an "@name = value" variable declaration
+// from a request block's preamble; trimmed is the line with
+// leading/trailing whitespace already removed. It shares "@name = ..."
+// syntax with parseCaptureLine, but only ever appears before a block's
+// request line, whereas a capture only ever appears after one.
+func parseDeclarationLine(trimmed string) (name, value string, err error) {
+	body := strings.TrimPrefix(trimmed, "@")
+	name, value, ok := strings.Cut(body, "=")
+	if !ok {
+		return "", "", fmt.Errorf("malformed declaration %q, expected @name = value", trimmed)
+	}
+	name = strings.TrimSpace(name)
+	value = strings.TrimSpace(value)
+	if name == "" {
+		return "", "", fmt.Errorf("empty declaration name in %q", trimmed)
+	}
+	return name, value, nil
+}
+
+// isFoldedContinuation reports whether line is an RFC 7230 obsolete
+// line-folded header continuation: one that starts with a space or tab
+// rather than a header name.
+func isFoldedContinuation(line string) bool {
+	return len(line) > 0 && (line[0] == ' ' || line[0] == '\t')
+}
+
+// appendFoldedLine folds line into the value of the most recently
+// parsed header on cur, per RFC 7230 section 3.2.4's obsolete
+// line-folding rule, and returns a warning describing what it did.
+// Folding is deprecated precisely because it's easy to misparse, so
+// Parse supports it but always flags it rather than accepting it
+// silently.
+func appendFoldedLine(cur *models.Request, line string, lineNo int) string {
+	last := &cur.RawHeaders[len(cur.RawHeaders)-1]
+	continuation := strings.TrimSpace(line)
+	last.Value = last.Value + " " + continuation
+
+	values := cur.Header[http.CanonicalHeaderKey(last.Name)]
+	if len(values) > 0 {
+		values[len(values)-1] = last.Value
+	}
+
+	return fmt.Sprintf("line %d: obsolete line-folded continuation for header %q; RFC 7230 deprecates folding", lineNo, last.Name)
+}
+
+func parseHeaderLine(line string) (name, value string, err error) {
+	idx := strings.Index(line, ":")
+	if idx < 0 {
+		return "", "", fmt.Errorf("malformed header %q", line)
+	}
+	name = strings.TrimSpace(line[:idx])
+	value = strings.TrimSpace(line[idx+1:])
+	if name == "" {
+		return "", "", fmt.Errorf("empty header name in %q", line)
+	}
+	return name, value, nil
+}
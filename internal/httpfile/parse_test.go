@@ -0,0 +1,315 @@
+package httpfile
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+func TestParse_SingleRequest(t *testing.T) {
+	input := `GET https://example.com/path?foo=bar HTTP/1.1
+Accept: application/json
+
+`
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	r := reqs[0]
+	if r.Method != "GET" {
+		t.Errorf("expected GET, got %q", r.Method)
+	}
+	if r.URL.String() != "https://example.com/path?foo=bar" {
+		t.Errorf("unexpected URL: %s", r.URL.String())
+	}
+	if got := r.Header.Get("Accept"); got != "application/json" {
+		t.Errorf("expected Accept header, got %q", got)
+	}
+}
+
+func TestParse_MultipleRequestsSeparatedByHashes(t *testing.T) {
+	input := `### first
+GET https://example.com/a
+
+### second
+POST https://example.com/b
+Content-Type: text/plain
+
+hello
+`
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(reqs))
+	}
+	if reqs[0].Name != "first" || reqs[1].Name != "second" {
+		t.Errorf("unexpected names: %q, %q", reqs[0].Name, reqs[1].Name)
+	}
+	if reqs[1].Body != "hello" {
+		t.Errorf("expected body %q, got %q", "hello", reqs[1].Body)
+	}
+}
+
+func TestParse_OptionsAsteriskForm(t *testing.T) {
+	input := "OPTIONS * HTTP/1.1\nHost: example.com\n\n"
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	r := reqs[0]
+	if r.Method != "OPTIONS" {
+		t.Errorf("expected OPTIONS, got %q", r.Method)
+	}
+	if r.URL.Path != "*" {
+		t.Errorf("expected asterisk-form path, got %q", r.URL.Path)
+	}
+	if !r.IsAsteriskForm() {
+		t.Error("expected IsAsteriskForm to be true")
+	}
+}
+
+func TestParse_ConnectAuthorityForm(t *testing.T) {
+	input := "CONNECT example.com:443 HTTP/1.1\n\n"
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	r := reqs[0]
+	if r.Method != "CONNECT" {
+		t.Errorf("expected CONNECT, got %q", r.Method)
+	}
+	if r.URL.Host != "example.com:443" {
+		t.Errorf("expected host:port %q, got %q", "example.com:443", r.URL.Host)
+	}
+	if r.URL.Scheme != "" {
+		t.Errorf("expected no scheme for CONNECT, got %q", r.URL.Scheme)
+	}
+	if !r.IsConnect() {
+		t.Error("expected IsConnect to be true")
+	}
+}
+
+func TestParse_CapturesResponseVariableDirectives(t *testing.T) {
+	input := `### login
+POST https://example.com/login
+@token = response.body.$.access_token
+@loc = response.headers.Location
+Content-Type: application/json
+
+{}
+
+### whoami
+GET https://example.com/me
+Authorization: Bearer {{token}}
+
+`
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(reqs))
+	}
+
+	login := reqs[0]
+	if len(login.Captures) != 2 {
+		t.Fatalf("expected 2 captures, got %d: %+v", len(login.Captures), login.Captures)
+	}
+	if login.Captures[0].Name != "token" || login.Captures[0].Expr != "response.body.$.access_token" {
+		t.Errorf("unexpected capture: %+v", login.Captures[0])
+	}
+	if login.Captures[1].Name != "loc" || login.Captures[1].Expr != "response.headers.Location" {
+		t.Errorf("unexpected capture: %+v", login.Captures[1])
+	}
+	if got := login.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected Content-Type header to still parse, got %q", got)
+	}
+
+	if got := reqs[1].Header.Get("Authorization"); got != "Bearer {{token}}" {
+		t.Errorf("expected unresolved placeholder in header, got %q", got)
+	}
+}
+
+func TestParse_ParsesDeclarationsBeforeTheRequestLine(t *testing.T) {
+	input := `@host = example.com
+@base = https://{{host}}
+GET {{base}}/path
+X-Base: {{base}}
+
+`
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	r := reqs[0]
+	if len(r.Declarations) != 2 {
+		t.Fatalf("expected 2 declarations, got %d: %+v", len(r.Declarations), r.Declarations)
+	}
+	if r.Declarations[0].Name != "host" || r.Declarations[0].Value != "example.com" {
+		t.Errorf("unexpected declaration: %+v", r.Declarations[0])
+	}
+	if r.Declarations[1].Name != "base" || r.Declarations[1].Value != "https://{{host}}" {
+		t.Errorf("unexpected declaration: %+v", r.Declarations[1])
+	}
+	if r.RawTarget != "{{base}}/path" {
+		t.Errorf("expected the request line's placeholder left unresolved for later chain resolution, got %q", r.RawTarget)
+	}
+	if got := r.Header.Get("X-Base"); got != "{{base}}" {
+		t.Errorf("expected the header's placeholder left unresolved for later chain resolution, got %q", got)
+	}
+}
+
+func TestParse_ParsesExpectDirectives(t *testing.T) {
+	input := `POST https://example.com/widgets
+# @expect status 201
+Content-Type: application/json
+// @expect header Content-Type: application/json
+# @expect body-contains "id"
+
+{"name": "widget"}
+`
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+
+	exps := reqs[0].Expectations
+	if len(exps) != 3 {
+		t.Fatalf("expected 3 expectations, got %d: %+v", len(exps), exps)
+	}
+	if exps[0].Kind != models.ExpectStatus || exps[0].Status != 201 {
+		t.Errorf("unexpected expectation: %+v", exps[0])
+	}
+	if exps[1].Kind != models.ExpectHeader || exps[1].Header != "Content-Type" || exps[1].Value != "application/json" {
+		t.Errorf("unexpected expectation: %+v", exps[1])
+	}
+	if exps[2].Kind != models.ExpectBodyContains || exps[2].Value != `"id"` {
+		t.Errorf("unexpected expectation: %+v", exps[2])
+	}
+	if got := reqs[0].Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected Content-Type header to still parse, got %q", got)
+	}
+}
+
+func TestParse_MalformedExpectDirectiveErrors(t *testing.T) {
+	input := `GET https://example.com/
+# @expect status not-a-number
+
+`
+	if _, _, err := Parse(strings.NewReader(input), 0); err == nil {
+		t.Fatal("expected an error for a malformed @expect directive")
+	}
+}
+
+func TestParse_SkipsCommentLinesBeforeTheBody(t *testing.T) {
+	input := `# top-level comment before any request
+// another style of comment
+
+### login
+# a comment above the request line
+POST https://example.com/login
+// a comment between headers
+Content-Type: application/json
+# yet another one
+
+{}
+`
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	req := reqs[0]
+	if req.Method != http.MethodPost {
+		t.Errorf("expected POST, got %q", req.Method)
+	}
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("expected Content-Type header to still parse, got %q", got)
+	}
+	if req.Body != "{}" {
+		t.Errorf("expected body %q, got %q", "{}", req.Body)
+	}
+}
+
+func TestParse_PreservesHashCharacterInBody(t *testing.T) {
+	input := `POST https://example.com/issues
+Content-Type: application/json
+
+{"note": "see issue #42"}
+`
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	if want := `{"note": "see issue #42"}`; reqs[0].Body != want {
+		t.Errorf("body = %q, want %q", reqs[0].Body, want)
+	}
+}
+
+func TestParse_FoldsObsoleteLineContinuations(t *testing.T) {
+	input := "GET https://example.com/\r\n" +
+		"X-Long: first\r\n" +
+		" second\r\n" +
+		"\tthird\r\n" +
+		"\r\n"
+	reqs, warnings, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(reqs))
+	}
+	if got, want := reqs[0].Header.Get("X-Long"), "first second third"; got != want {
+		t.Errorf("X-Long = %q, want %q", got, want)
+	}
+	if len(warnings) != 2 {
+		t.Fatalf("expected a warning per folded continuation line, got %v", warnings)
+	}
+	for _, w := range warnings {
+		if !strings.Contains(w, "folded") {
+			t.Errorf("expected a folding warning, got %q", w)
+		}
+	}
+}
+
+func TestParse_ErrorsPastMaxHeaderLines(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("GET https://example.com/\n")
+	for i := 0; i < 10; i++ {
+		fmt.Fprintf(&b, "X-Header-%d: value\n", i)
+	}
+	b.WriteString("\n")
+
+	if _, _, err := Parse(strings.NewReader(b.String()), 5); err == nil {
+		t.Fatal("expected an error when a request's headers exceed maxHeaderLines")
+	}
+
+	if _, _, err := Parse(strings.NewReader(b.String()), 20); err != nil {
+		t.Fatalf("expected no error under the limit, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package httpfile
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+func TestWrite_RoundTripsThroughParse(t *testing.T) {
+	input := `POST https://example.com/submit HTTP/1.1
+Content-Type: application/json
+X-Trace: abc
+
+{"a":1}
+`
+	reqs, _, err := Parse(strings.NewReader(input), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, reqs[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	reparsed, _, err := Parse(&buf, 0)
+	if err != nil {
+		t.Fatalf("re-parsing Write's output failed: %v\n%s", err, buf.String())
+	}
+	if len(reparsed) != 1 {
+		t.Fatalf("expected 1 request after round-trip, got %d", len(reparsed))
+	}
+	got := reparsed[0]
+	if got.Method != "POST" || got.URL.String() != "https://example.com/submit" {
+		t.Errorf("expected the request line to survive, got %s %s", got.Method, got.URL)
+	}
+	if got.Header.Get("Content-Type") != "application/json" || got.Header.Get("X-Trace") != "abc" {
+		t.Errorf("expected headers to survive, got %v", got.Header)
+	}
+	if got.Body != `{"a":1}` {
+		t.Errorf("expected body to survive, got %q", got.Body)
+	}
+}
+
+func TestWrite_UsesRawHeaderCasingWhenPresent(t *testing.T) {
+	req := models.New()
+	req.Method = http.MethodGet
+	req.URL = &url.URL{Scheme: "http", Host: "example.com", Path: "/"}
+	req.Header.Add("x-REQUEST-id", "abc123")
+	req.RawHeaders = append(req.RawHeaders, models.RawHeader{Name: "x-REQUEST-id", Value: "abc123"})
+
+	var buf bytes.Buffer
+	if err := Write(&buf, req); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "x-REQUEST-id: abc123") {
+		t.Errorf("expected the original header casing to be written, got %q", buf.String())
+	}
+}
+
+func TestWrite_FallsBackToSortedCanonicalHeadersWithoutRawHeaders(t *testing.T) {
+	req := models.New()
+	req.Method = http.MethodGet
+	req.URL = &url.URL{Scheme: "http", Host: "example.com", Path: "/"}
+	req.Header.Set("Zebra", "z")
+	req.Header.Set("Alpha", "a")
+
+	var buf bytes.Buffer
+	if err := Write(&buf, req); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	if strings.Index(out, "Alpha: a") > strings.Index(out, "Zebra: z") {
+		t.Errorf("expected headers sorted by name, got %q", out)
+	}
+}
@@ -0,0 +1,151 @@
+// Package idna converts internationalized domain names to their ASCII
+// "xn--" punycode form (RFC 3492/5891), so a host containing non-ASCII
+// characters can be sent as a valid Host header and TLS SNI value. The
+// project has no external dependencies, so this is a small
+// self-contained implementation rather than golang.org/x/net/idna.
+package idna
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	base         = 36
+	tmin         = 1
+	tmax         = 26
+	skew         = 38
+	damp         = 700
+	initialBias  = 72
+	initialN     = 128
+	acePrefix    = "xn--"
+	delimiterRne = '-'
+)
+
+// ToASCII converts host to its ASCII form, punycode-encoding any label
+// that contains non-ASCII characters. It reports whether host was
+// actually changed, so callers can decide whether to warn. A host with
+// no non-ASCII labels is returned unchanged.
+func ToASCII(host string) (ascii string, changed bool, err error) {
+	labels := strings.Split(host, ".")
+	for i, label := range labels {
+		if isASCII(label) {
+			continue
+		}
+		encoded, err := encodeLabel(label)
+		if err != nil {
+			return "", false, fmt.Errorf("idna: label %q: %w", label, err)
+		}
+		labels[i] = acePrefix + encoded
+		changed = true
+	}
+	if !changed {
+		return host, false, nil
+	}
+	return strings.Join(labels, "."), true, nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeLabel implements the RFC 3492 Punycode encoding algorithm for a
+// single domain label.
+func encodeLabel(label string) (string, error) {
+	runes := []rune(label)
+
+	var output []byte
+	for _, r := range runes {
+		if r < 0x80 {
+			output = append(output, byte(r))
+		}
+	}
+	basicCount := len(output)
+	if basicCount > 0 {
+		output = append(output, delimiterRne)
+	}
+
+	n := initialN
+	delta := 0
+	bias := initialBias
+	h := basicCount
+
+	for h < len(runes) {
+		m := -1
+		for _, r := range runes {
+			if int(r) >= n && (m == -1 || int(r) < m) {
+				m = int(r)
+			}
+		}
+		if m == -1 {
+			return "", fmt.Errorf("no code point found above %d", n)
+		}
+
+		delta += (m - n) * (h + 1)
+		n = m
+
+		for _, r := range runes {
+			if int(r) < n {
+				delta++
+			}
+			if int(r) == n {
+				q := delta
+				for k := base; ; k += base {
+					t := clamp(k-bias, tmin, tmax)
+					if q < t {
+						break
+					}
+					output = append(output, encodeDigit(t+(q-t)%(base-t)))
+					q = (q - t) / (base - t)
+				}
+				output = append(output, encodeDigit(q))
+				bias = adapt(delta, h+1, h == basicCount)
+				delta = 0
+				h++
+			}
+		}
+		delta++
+		n++
+	}
+
+	return string(output), nil
+}
+
+func encodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+	return byte('0' + d - 26)
+}
+
+func clamp(k, lo, hi int) int {
+	switch {
+	case k < lo:
+		return lo
+	case k > hi:
+		return hi
+	default:
+		return k
+	}
+}
+
+func adapt(delta, numPoints int, firstTime bool) int {
+	if firstTime {
+		delta /= damp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+
+	k := 0
+	for delta > ((base-tmin)*tmax)/2 {
+		delta /= base - tmin
+		k += base
+	}
+	return k + ((base-tmin+1)*delta)/(delta+skew)
+}
@@ -0,0 +1,42 @@
+package idna
+
+import "testing"
+
+func TestToASCII_ConvertsUnicodeLabel(t *testing.T) {
+	got, changed, err := ToASCII("café.example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("expected changed to be true")
+	}
+	if got != "xn--caf-dma.example" {
+		t.Errorf("got %q, want %q", got, "xn--caf-dma.example")
+	}
+}
+
+func TestToASCII_AllASCIILeavesHostUnchanged(t *testing.T) {
+	got, changed, err := ToASCII("example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if changed {
+		t.Error("did not expect changed to be true for an ASCII host")
+	}
+	if got != "example.com" {
+		t.Errorf("got %q, want unchanged %q", got, "example.com")
+	}
+}
+
+func TestToASCII_ConvertsSingleLabelHost(t *testing.T) {
+	got, changed, err := ToASCII("münchen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !changed {
+		t.Error("expected changed to be true")
+	}
+	if got != "xn--mnchen-3ya" {
+		t.Errorf("got %q, want %q", got, "xn--mnchen-3ya")
+	}
+}
@@ -0,0 +1,100 @@
+package models
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+)
+
+func mixedCaseRequest() *Request {
+	req := New()
+	req.Method = http.MethodGet
+	req.URL = &url.URL{Scheme: "http", Host: "example.com", Path: "/"}
+	req.Header.Add("x-REQUEST-id", "abc123")
+	req.RawHeaders = append(req.RawHeaders, RawHeader{Name: "x-REQUEST-id", Value: "abc123"})
+	return req
+}
+
+func TestWireHeaders_PreserveKeepsOriginalCasing(t *testing.T) {
+	req := mixedCaseRequest()
+
+	headers := req.WireHeaders("preserve")
+	got, ok := headers["x-REQUEST-id"]
+	if !ok || len(got) != 1 || got[0] != "abc123" {
+		t.Errorf("expected the map key to keep its original casing %q with value %q, got keys %v", "x-REQUEST-id", "abc123", headerKeys(headers))
+	}
+}
+
+func TestWireHeaders_CanonicalUsesCanonicalMIMEHeaderKey(t *testing.T) {
+	req := mixedCaseRequest()
+
+	headers := req.WireHeaders("canonical")
+	if _, ok := headers["X-Request-Id"]; !ok {
+		t.Errorf("expected the map key to be canonicalized to %q, got keys %v", "X-Request-Id", headerKeys(headers))
+	}
+}
+
+func TestWireHeaders_LowerLowercasesEveryName(t *testing.T) {
+	req := mixedCaseRequest()
+
+	headers := req.WireHeaders("lower")
+	if _, ok := headers["x-request-id"]; !ok {
+		t.Errorf("expected the map key to be all lowercase %q, got keys %v", "x-request-id", headerKeys(headers))
+	}
+}
+
+func TestWireHeaders_EmptyRawHeadersFallsBackToHeader(t *testing.T) {
+	req := New()
+	req.Header.Set("X-Request-Id", "abc123")
+
+	headers := req.WireHeaders("preserve")
+	if got := headers.Get("X-Request-Id"); got != "abc123" {
+		t.Errorf("expected Header's own casing when RawHeaders is empty, got %q", got)
+	}
+}
+
+func TestHTTPRequest_HeaderCaseControlsBuiltRequestHeaderKeys(t *testing.T) {
+	req := mixedCaseRequest()
+
+	httpReq, err := req.HTTPRequest("lower")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := httpReq.Header["x-request-id"]; !ok {
+		t.Errorf("expected the built request's header map to use lowercase keys, got %v", headerKeys(httpReq.Header))
+	}
+}
+
+func TestSetHeader_UpdatesExistingRawHeaderInPlace(t *testing.T) {
+	req := mixedCaseRequest()
+
+	req.SetHeader("X-Request-Id", "def456")
+
+	if got := req.Header.Get("X-Request-Id"); got != "def456" {
+		t.Errorf("expected Header to be updated, got %q", got)
+	}
+	if len(req.RawHeaders) != 1 {
+		t.Fatalf("expected SetHeader to update the existing raw header rather than append, got %v", req.RawHeaders)
+	}
+	if req.RawHeaders[0].Name != "x-REQUEST-id" || req.RawHeaders[0].Value != "def456" {
+		t.Errorf("expected the raw header's original name to survive with the new value, got %+v", req.RawHeaders[0])
+	}
+}
+
+func TestSetHeader_AppendsRawHeaderWhenNotPreviouslySet(t *testing.T) {
+	req := New()
+
+	req.SetHeader("Content-Type", "application/json")
+
+	if len(req.RawHeaders) != 1 || req.RawHeaders[0].Name != "Content-Type" {
+		t.Errorf("expected a new raw header to be recorded, got %v", req.RawHeaders)
+	}
+}
+
+func headerKeys(h http.Header) []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,113 @@
+package models
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/adamijak/http/internal/theme"
+)
+
+// colorizeJSON re-emits body with t's ANSI colors for JSON keys,
+// strings, numbers, booleans/null, and punctuation, operating on the
+// raw text so the original formatting (indentation, key order) is
+// preserved. It reports false, leaving body untouched, when body
+// doesn't parse as JSON.
+func colorizeJSON(body string, t theme.Theme) (string, bool) {
+	trimmed := strings.TrimSpace(body)
+	if trimmed == "" || !json.Valid([]byte(trimmed)) {
+		return body, false
+	}
+
+	var out strings.Builder
+	runes := []rune(body)
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == '"':
+			j := scanString(runes, i)
+			out.WriteString(colorFor(runes, i, j, t))
+			out.WriteString(string(runes[i:j]))
+			out.WriteString(t.Reset)
+			i = j
+		case strings.ContainsRune("{}[],:", c):
+			out.WriteString(t.Punct)
+			out.WriteRune(c)
+			out.WriteString(t.Reset)
+			i++
+		case c == '-' || (c >= '0' && c <= '9'):
+			j := scanNumber(runes, i)
+			out.WriteString(t.Number)
+			out.WriteString(string(runes[i:j]))
+			out.WriteString(t.Reset)
+			i = j
+		case matchesLiteral(runes, i, "true"), matchesLiteral(runes, i, "false"), matchesLiteral(runes, i, "null"):
+			j := i + literalLen(runes, i)
+			out.WriteString(t.Literal)
+			out.WriteString(string(runes[i:j]))
+			out.WriteString(t.Reset)
+			i = j
+		default:
+			out.WriteRune(c)
+			i++
+		}
+	}
+	return out.String(), true
+}
+
+// scanString returns the index just past the closing quote of the
+// string starting at i, honoring backslash escapes.
+func scanString(runes []rune, i int) int {
+	j := i + 1
+	for j < len(runes) {
+		if runes[j] == '\\' {
+			j += 2
+			continue
+		}
+		if runes[j] == '"' {
+			return j + 1
+		}
+		j++
+	}
+	return j
+}
+
+func scanNumber(runes []rune, i int) int {
+	j := i
+	if runes[j] == '-' {
+		j++
+	}
+	for j < len(runes) && strings.ContainsRune("0123456789.eE+-", runes[j]) {
+		j++
+	}
+	return j
+}
+
+func matchesLiteral(runes []rune, i int, literal string) bool {
+	lr := []rune(literal)
+	if i+len(lr) > len(runes) {
+		return false
+	}
+	return string(runes[i:i+len(lr)]) == literal
+}
+
+func literalLen(runes []rune, i int) int {
+	for _, l := range []string{"true", "false", "null"} {
+		if matchesLiteral(runes, i, l) {
+			return len(l)
+		}
+	}
+	return 0
+}
+
+// colorFor decides whether the string token at [i:j) is an object key
+// (followed, after whitespace, by a colon) or a string value.
+func colorFor(runes []rune, i, j int, t theme.Theme) string {
+	k := j
+	for k < len(runes) && (runes[k] == ' ' || runes[k] == '\t' || runes[k] == '\n' || runes[k] == '\r') {
+		k++
+	}
+	if k < len(runes) && runes[k] == ':' {
+		return t.Key
+	}
+	return t.String
+}
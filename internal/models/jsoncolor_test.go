@@ -0,0 +1,61 @@
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/adamijak/http/internal/theme"
+)
+
+func TestColorizeJSON_NestedDocument(t *testing.T) {
+	body := `{"name":"ada","age":36,"active":true,"tags":["admin",null],"meta":{"score":1.5}}`
+
+	got, ok := colorizeJSON(body, theme.Default)
+	if !ok {
+		t.Fatalf("colorizeJSON(%q) reported not-JSON", body)
+	}
+
+	cases := []struct {
+		color string
+		token string
+	}{
+		{theme.Default.Key, `"name"`},
+		{theme.Default.String, `"ada"`},
+		{theme.Default.Key, `"age"`},
+		{theme.Default.Number, "36"},
+		{theme.Default.Key, `"active"`},
+		{theme.Default.Literal, "true"},
+		{theme.Default.Key, `"tags"`},
+		{theme.Default.String, `"admin"`},
+		{theme.Default.Literal, "null"},
+		{theme.Default.Key, `"meta"`},
+		{theme.Default.Number, "1.5"},
+	}
+	for _, c := range cases {
+		want := c.color + c.token + theme.Default.Reset
+		if !strings.Contains(got, want) {
+			t.Errorf("colorizeJSON output missing %q colored as %q\nfull output: %q", c.token, c.color, got)
+		}
+	}
+}
+
+func TestColorizeJSON_InvalidJSONFallsBack(t *testing.T) {
+	body := "not json at all"
+	got, ok := colorizeJSON(body, theme.Default)
+	if ok {
+		t.Fatalf("colorizeJSON(%q) reported JSON, want fallback", body)
+	}
+	if got != body {
+		t.Errorf("colorizeJSON(%q) = %q, want unchanged body", body, got)
+	}
+}
+
+func TestColorizeJSON_EmptyBodyFallsBack(t *testing.T) {
+	got, ok := colorizeJSON("", theme.Default)
+	if ok {
+		t.Fatalf("colorizeJSON(\"\") reported JSON, want fallback")
+	}
+	if got != "" {
+		t.Errorf("colorizeJSON(\"\") = %q, want empty", got)
+	}
+}
@@ -0,0 +1,347 @@
+// Package models defines the in-memory representations of an HTTP
+// request and response used throughout the CLI, along with helpers for
+// building the outgoing net/http request and printing the response.
+package models
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// RawHeader is a single header exactly as it appeared in the .http
+// file, before Header folds it into net/http's canonicalized map and
+// discards the original name casing. --header-case reads these to
+// decide what casing to put on the wire; everything else in the CLI
+// keeps using Header, whose Get/Set/Add are case-insensitive.
+type RawHeader struct {
+	Name  string
+	Value string
+}
+
+// Capture names a value to extract from this request's response and
+// store under Name, so a later request block in the same file can
+// reference it as "{{Name}}". Expr is one of:
+//
+//	response.headers.<Header-Name>
+//	response.body.$.<dot.separated.json.path>
+type Capture struct {
+	Name string
+	Expr string
+}
+
+// Declaration is an "@name = value" variable declaration parsed from a
+// request block's preamble (the lines before its request line), giving
+// the whole file a value to reference as "{{Name}}" without having to
+// wait for a response, unlike Capture. Value may itself contain
+// "{{name}}" placeholders referencing an earlier declaration or a
+// process environment variable.
+type Declaration struct {
+	Name  string
+	Value string
+}
+
+// Expectation is an "# @expect ..." directive declared in a request
+// block, checked against that request's response once it's been sent.
+// It's plain data rather than a function so a .http file's directives
+// and the CLI's global --expect-* flags can share one representation
+// without the evaluation logic (which lives in the cli package)
+// importing back into models.
+type Expectation struct {
+	// Kind selects which fields below are meaningful: "status",
+	// "header", or "body-contains".
+	Kind ExpectationKind
+
+	// Status is the expected status code, for Kind == ExpectStatus.
+	Status int
+
+	// Header is the expected header name, for Kind == ExpectHeader.
+	Header string
+
+	// Value is the expected header value (Kind == ExpectHeader) or body
+	// substring (Kind == ExpectBodyContains); it's matched as a regular
+	// expression when it compiles as one, and as a plain substring
+	// otherwise.
+	Value string
+}
+
+// ExpectationKind names the kind of check an Expectation performs.
+type ExpectationKind string
+
+const (
+	ExpectStatus       ExpectationKind = "status"
+	ExpectHeader       ExpectationKind = "header"
+	ExpectBodyContains ExpectationKind = "body-contains"
+)
+
+// Request is a single request parsed from a .http file. It mirrors the
+// pieces of an HTTP request the CLI needs to inspect, mutate, and print
+// before it is ever sent over the wire.
+type Request struct {
+	// Name is the optional label following a "###" separator.
+	Name string
+
+	// SourceFile is the path of the .http file this request was parsed
+	// from, set by the CLI once multiple files may be given on the
+	// command line. It's empty for requests built directly (e.g. in
+	// tests), which is fine since it's display-only.
+	SourceFile string
+
+	Method string
+	URL    *url.URL
+
+	// RawTarget is the request-target exactly as written on the request
+	// line, before URL parsing. It's kept alongside URL because a target
+	// containing "{{var}}" placeholders can't be parsed as a URL until
+	// chain.Store.Resolve has substituted captured values into it.
+	RawTarget string
+
+	Proto  string
+	Header http.Header
+	Body   string
+
+	// BodyReader, when non-nil, provides the request body lazily as it's
+	// sent, taking precedence over Body, so uploading a file larger than
+	// available memory (--upload-file, --body-stdin) doesn't require
+	// buffering it into a string first. Print can't show its contents
+	// without consuming them, so it prints a placeholder instead of the
+	// real body when this is set.
+	BodyReader io.Reader
+
+	// BodyLength is BodyReader's length in bytes, when known (e.g. from
+	// stat'ing an uploaded file), so HTTPRequest can send a
+	// Content-Length instead of falling back to chunked transfer
+	// encoding. 0 means unknown; ignored when BodyReader is nil.
+	BodyLength int64
+
+	// RawHeaders mirrors Header's contents in file order and original
+	// name casing. It's populated by httpfile.Parse and kept in sync by
+	// SetHeader as the CLI adds or rewrites headers (Host,
+	// Content-Length, Cookie, ...); a Request built directly (e.g. in
+	// tests) leaves it empty, which WireHeaders treats as "fall back to
+	// Header".
+	RawHeaders []RawHeader
+
+	// Captures are the "@name = response...." directives declared in
+	// this block, extracted from this request's response once it's been
+	// sent.
+	Captures []Capture
+
+	// Declarations are the "@name = value" variable declarations found
+	// in this block's preamble, before its request line.
+	Declarations []Declaration
+
+	// Expectations are the "# @expect ..." directives declared in this
+	// block, checked against this request's response once it's been
+	// sent.
+	Expectations []Expectation
+}
+
+// New returns an empty Request with an initialized header map.
+func New() *Request {
+	return &Request{
+		Header: make(http.Header),
+	}
+}
+
+// SetHeader sets name to value on both Header (used for every
+// case-insensitive lookup elsewhere: validate, chain resolution, cookie
+// attachment) and RawHeaders (used only to decide wire casing),
+// replacing any existing value for name in each.
+func (r *Request) SetHeader(name, value string) {
+	r.Header.Set(name, value)
+	for i, h := range r.RawHeaders {
+		if strings.EqualFold(h.Name, name) {
+			r.RawHeaders[i].Value = value
+			return
+		}
+	}
+	r.RawHeaders = append(r.RawHeaders, RawHeader{Name: name, Value: value})
+}
+
+// DeleteHeader removes every value for name from both Header and
+// RawHeaders, the counterpart to SetHeader.
+func (r *Request) DeleteHeader(name string) {
+	r.Header.Del(name)
+	kept := r.RawHeaders[:0]
+	for _, h := range r.RawHeaders {
+		if !strings.EqualFold(h.Name, name) {
+			kept = append(kept, h)
+		}
+	}
+	r.RawHeaders = kept
+}
+
+// WireHeaders returns the headers to send on the wire, cased according
+// to headerCase:
+//
+//	"", "preserve" - each header's name exactly as written in the file
+//	"canonical"    - textproto's Ident-Style ("Content-Type")
+//	"lower"        - all lowercase, as HTTP/2 requires
+//
+// When r has no RawHeaders (built directly rather than parsed from a
+// file), it falls back to Header, which is always canonical.
+func (r *Request) WireHeaders(headerCase string) http.Header {
+	if len(r.RawHeaders) == 0 {
+		return r.Header.Clone()
+	}
+	out := make(http.Header, len(r.RawHeaders))
+	for _, h := range r.RawHeaders {
+		name := h.Name
+		switch headerCase {
+		case "canonical":
+			name = http.CanonicalHeaderKey(name)
+		case "lower":
+			name = strings.ToLower(name)
+		}
+		out[name] = append(out[name], h.Value)
+	}
+	return out
+}
+
+// HTTPRequest converts r into a *http.Request ready to be sent by
+// net/http, cloning the header so later mutation of r doesn't affect an
+// already-built request. headerCase controls the wire casing of header
+// names; see WireHeaders. The request line's protocol version is taken
+// from r.Proto (defaulting to HTTP/1.1), so an HTTP/1.0 request is
+// written with HTTP/1.0 framing: no chunked transfer-encoding, and a
+// Connection: close request that net/http and the server both honor.
+func (r *Request) HTTPRequest(headerCase string) (*http.Request, error) {
+	target := r.URL.String()
+	switch {
+	case r.IsAsteriskForm():
+		host := r.Header.Get("Host")
+		if host == "" {
+			return nil, fmt.Errorf("request: OPTIONS * requires an explicit Host header")
+		}
+		target = "http://" + host + "/"
+	case r.IsConnect():
+		if r.URL.Host == "" {
+			return nil, fmt.Errorf("request: CONNECT requires a host:port target")
+		}
+		target = "http://" + r.URL.Host
+	}
+
+	var body io.Reader = strings.NewReader(r.Body)
+	if r.BodyReader != nil {
+		body = r.BodyReader
+	}
+	req, err := http.NewRequest(r.Method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	if r.BodyReader != nil && r.BodyLength > 0 {
+		req.ContentLength = r.BodyLength
+	}
+	if r.IsAsteriskForm() {
+		// http.NewRequest parsed a placeholder "/" path; restore the
+		// asterisk-form target net/http's own Request.Write knows to send
+		// verbatim (see net/http's testStarRequest).
+		req.URL.Path = "*"
+		req.URL.RawPath = ""
+	}
+	req.Header = r.WireHeaders(headerCase)
+	if host := req.Header.Get("Host"); host != "" && !r.IsAsteriskForm() {
+		// net/http writes the wire Host line from req.Host (falling back
+		// to req.URL.Host), not from the Header map, so a request's own
+		// "Host:" header has to be threaded through explicitly to take
+		// effect. Asterisk-form already gets this for free: its target
+		// is built directly from the Host header above.
+		req.Host = host
+	}
+
+	proto := r.Proto
+	switch proto {
+	case "":
+		proto = "HTTP/1.1"
+	case "HTTP/2":
+		// net/http's own textual form for HTTP/2 requires a minor
+		// version; ParseHTTPVersion doesn't accept the bare "HTTP/2"
+		// that a .http file naturally writes.
+		proto = "HTTP/2.0"
+	}
+	major, minor, ok := http.ParseHTTPVersion(proto)
+	if !ok {
+		return nil, fmt.Errorf("request: invalid protocol version %q", proto)
+	}
+	req.Proto = proto
+	req.ProtoMajor = major
+	req.ProtoMinor = minor
+
+	return req, nil
+}
+
+// RequestPrintOptions controls how Request.Print formats its output.
+type RequestPrintOptions struct {
+	// Headers includes the request line and headers.
+	Headers bool
+	// Body includes the request body.
+	Body bool
+
+	// AlignHeaders pads every printed header name to the width of the
+	// longest one, so their colons line up in a column. Purely a display
+	// choice; see HTTPResponse's PrintOptions.AlignHeaders.
+	AlignHeaders bool
+}
+
+// Print writes r's request line, headers, and body to w, according to
+// opts, cased on the wire the same way headerCase would render them for
+// sending. It's the request-side counterpart to HTTPResponse.Print,
+// used by --print to show what was sent alongside what came back.
+func (r *Request) Print(w io.Writer, headerCase string, opts RequestPrintOptions) {
+	if !opts.Headers && !opts.Body {
+		return
+	}
+	if opts.Headers {
+		target := r.RawTarget
+		if target == "" && r.URL != nil {
+			target = r.URL.RequestURI()
+		}
+		proto := r.Proto
+		if proto == "" {
+			proto = "HTTP/1.1"
+		}
+		fmt.Fprintf(w, "%s %s %s\n", r.Method, target, proto)
+		printHeaders(w, r.WireHeaders(headerCase), opts.AlignHeaders)
+		fmt.Fprintln(w)
+	}
+	if opts.Body {
+		switch {
+		case r.BodyReader != nil && r.BodyLength > 0:
+			fmt.Fprintf(w, "<%d bytes streamed from file/stdin, not buffered for display>\n", r.BodyLength)
+		case r.BodyReader != nil:
+			fmt.Fprintln(w, "<streamed from file/stdin, not buffered for display>")
+		default:
+			fmt.Fprintln(w, r.Body)
+		}
+	}
+}
+
+// IsHTTP10 reports whether r declares HTTP/1.0, which doesn't mandate a
+// Host header and defaults to closing the connection after one
+// response.
+func (r *Request) IsHTTP10() bool {
+	return r.Proto == "HTTP/1.0"
+}
+
+// IsHTTP2 reports whether r declares HTTP/2, which is only sent as such
+// when the server negotiates "h2" via ALPN over TLS.
+func (r *Request) IsHTTP2() bool {
+	return r.Proto == "HTTP/2" || r.Proto == "HTTP/2.0"
+}
+
+// IsAsteriskForm reports whether r is an OPTIONS request using the
+// asterisk-form request-target ("OPTIONS * HTTP/1.1"), which applies to
+// the server as a whole rather than a specific resource and so carries
+// no scheme or host in its request line.
+func (r *Request) IsAsteriskForm() bool {
+	return r.Method == http.MethodOptions && r.URL != nil && r.URL.Path == "*"
+}
+
+// IsConnect reports whether r is a CONNECT request, whose target is
+// just the destination's "host:port" authority rather than a URL.
+func (r *Request) IsConnect() bool {
+	return r.Method == http.MethodConnect
+}
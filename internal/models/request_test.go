@@ -0,0 +1,116 @@
+package models
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestHTTPRequest_AsteriskFormUsesHostHeaderAndStarPath(t *testing.T) {
+	req := New()
+	req.Method = http.MethodOptions
+	req.URL = &url.URL{Path: "*"}
+	req.Header.Set("Host", "example.com")
+
+	httpReq, err := req.HTTPRequest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if httpReq.URL.Path != "*" {
+		t.Errorf("expected URL.Path %q, got %q", "*", httpReq.URL.Path)
+	}
+	if httpReq.URL.RequestURI() != "*" {
+		t.Errorf("expected RequestURI %q, got %q", "*", httpReq.URL.RequestURI())
+	}
+	if httpReq.URL.Host != "example.com" {
+		t.Errorf("expected Host %q, got %q", "example.com", httpReq.URL.Host)
+	}
+}
+
+func TestHTTPRequest_AsteriskFormWithoutHostHeaderErrors(t *testing.T) {
+	req := New()
+	req.Method = http.MethodOptions
+	req.URL = &url.URL{Path: "*"}
+
+	if _, err := req.HTTPRequest(""); err == nil {
+		t.Fatal("expected an error for OPTIONS * without a Host header")
+	}
+}
+
+func TestHTTPRequest_ConnectUsesAuthorityAsHost(t *testing.T) {
+	req := New()
+	req.Method = http.MethodConnect
+	req.URL = &url.URL{Host: "example.com:443"}
+
+	httpReq, err := req.HTTPRequest("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if httpReq.URL.Host != "example.com:443" {
+		t.Errorf("expected Host %q, got %q", "example.com:443", httpReq.URL.Host)
+	}
+	if httpReq.URL.Path != "" {
+		t.Errorf("expected an empty path for CONNECT, got %q", httpReq.URL.Path)
+	}
+}
+
+func TestIsAsteriskForm(t *testing.T) {
+	req := New()
+	req.Method = http.MethodOptions
+	req.URL = &url.URL{Path: "*"}
+	if !req.IsAsteriskForm() {
+		t.Error("expected IsAsteriskForm to be true for OPTIONS *")
+	}
+
+	req.Method = http.MethodGet
+	if req.IsAsteriskForm() {
+		t.Error("expected IsAsteriskForm to be false for a GET request")
+	}
+}
+
+func TestIsConnect(t *testing.T) {
+	req := New()
+	req.Method = http.MethodConnect
+	req.URL = &url.URL{Host: "example.com:443"}
+	if !req.IsConnect() {
+		t.Error("expected IsConnect to be true for a CONNECT request")
+	}
+}
+
+func TestPrint_HeadersOnlyOmitsBody(t *testing.T) {
+	req := New()
+	req.Method = http.MethodPost
+	req.URL = &url.URL{Path: "/widgets"}
+	req.SetHeader("Content-Type", "application/json")
+	req.Body = `{"name":"foo"}`
+
+	var buf bytes.Buffer
+	req.Print(&buf, "", RequestPrintOptions{Headers: true})
+
+	got := buf.String()
+	if !strings.HasPrefix(got, "POST /widgets HTTP/1.1\n") {
+		t.Errorf("expected a request line, got %q", got)
+	}
+	if !strings.Contains(got, "Content-Type: application/json") {
+		t.Errorf("expected the header to be printed, got %q", got)
+	}
+	if strings.Contains(got, "foo") {
+		t.Errorf("expected the body to be omitted, got %q", got)
+	}
+}
+
+func TestPrint_BodyOnlyOmitsRequestLine(t *testing.T) {
+	req := New()
+	req.Method = http.MethodPost
+	req.URL = &url.URL{Path: "/widgets"}
+	req.Body = "hello"
+
+	var buf bytes.Buffer
+	req.Print(&buf, "", RequestPrintOptions{Body: true})
+
+	if got := buf.String(); got != "hello\n" {
+		t.Errorf("expected just the body, got %q", got)
+	}
+}
@@ -0,0 +1,264 @@
+package models
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/adamijak/http/internal/theme"
+)
+
+// HTTPResponse is the result of sending a Request. It captures the
+// pieces of http.Response the CLI cares about, decoupled from the
+// lifetime of the underlying network connection.
+type HTTPResponse struct {
+	Proto      string
+	StatusCode int
+	Status     string
+	Header     http.Header
+	Body       string
+
+	// RawBody holds the response body's exact bytes, alongside Body's
+	// string conversion. Print uses it to detect binary content and to
+	// write untouched bytes when saving to a file; Body exists so text
+	// bodies remain easy to pattern-match and print without a
+	// conversion at every call site.
+	RawBody []byte
+
+	// Timing is the wall-clock duration of the round trip, measured
+	// around the underlying http.Client.Do call. Consumers such as
+	// --timing, --repeat, and the HAR exporter read it directly instead
+	// of timing requests themselves.
+	Timing time.Duration
+
+	// Cookies holds every Set-Cookie header, parsed. Multiple Set-Cookie
+	// headers are preserved since http.Header stores repeated headers as
+	// a slice and http.Response.Cookies parses all of them.
+	Cookies []*http.Cookie
+
+	// Truncated is true when Body was cut short by Options.MaxBodySize.
+	Truncated bool
+
+	// RedirectWarnings holds one entry per notable event noticed while
+	// following redirects to reach this response, e.g. an https->http
+	// downgrade. It's empty when the request wasn't redirected or
+	// nothing noteworthy happened along the way.
+	RedirectWarnings []string
+
+	// Redirects counts the redirect hops followed to reach this
+	// response. It's 0 when nothing redirected.
+	Redirects int
+
+	// CharsetWarning is set when Content-Type named a charset that
+	// couldn't be decoded to UTF-8 (unrecognized or unsupported), in
+	// which case Body holds body's raw bytes unconverted rather than
+	// mangled or dropped text. It's empty when no decoding was needed
+	// or decoding succeeded.
+	CharsetWarning string
+
+	// FinalURL is the URL of the request that actually produced this
+	// response, after following any redirects (curl's
+	// %{url_effective}). It equals the request's original URL when
+	// nothing redirected.
+	FinalURL string
+
+	// Trailer holds any trailer headers sent after a chunked body's
+	// terminating zero-size chunk (RFC 7230 §4.1.2), e.g. a checksum
+	// announced by a "Trailer:" header and computed only once the whole
+	// body has been streamed. It's separate from Header, which reflects
+	// only the headers sent before the body, since a trailer is only
+	// available once the body has been fully read; it's empty for a
+	// response with no trailers, or when Truncated stopped short of the
+	// terminating chunk.
+	Trailer http.Header
+}
+
+// PrintOptions controls how HTTPResponse.Print formats its output.
+type PrintOptions struct {
+	// Timing appends a trailing "time: <duration>" line.
+	Timing bool
+	// Color enables ANSI syntax highlighting of a JSON body. Non-JSON
+	// bodies, or bodies that fail to parse, print unchanged.
+	Color bool
+	// Theme selects the palette Color uses, by name (see
+	// internal/theme.Names). An empty or unrecognized name falls back to
+	// theme.Default.
+	Theme string
+
+	// BodyOnly suppresses the status line and headers, printing just the
+	// body, for piping the response into another program.
+	BodyOnly bool
+
+	// HeadersOnly suppresses the body (and any trailer), printing just
+	// the status line and headers, mirroring curl -I. It's the opposite
+	// of BodyOnly; the two are mutually exclusive.
+	HeadersOnly bool
+
+	// TrimBytes, when nonzero, prints only the body's first N bytes
+	// followed by a "[... N more bytes]" marker instead of the full
+	// body. This only affects what Print writes to the terminal; the
+	// response held in memory (and anything --output saves) is
+	// untouched. Mutually exclusive with TrimLines.
+	TrimBytes int
+
+	// TrimLines, when nonzero, prints only the body's first N lines
+	// followed by a "[... N more lines]" marker instead of the full
+	// body, for line-oriented bodies where a byte count cuts mid-line.
+	// Mutually exclusive with TrimBytes.
+	TrimLines int
+
+	// AlignHeaders pads every printed header name to the width of the
+	// longest one, so their colons line up in a column. Purely a display
+	// choice: it doesn't affect header order (already alphabetized either
+	// way) or anything sent on the wire.
+	AlignHeaders bool
+}
+
+// Print writes r's status line, headers, and body to w, unless
+// opts.BodyOnly is set, in which case only the body is written, or
+// opts.HeadersOnly is set, in which case the body (and trailer) are
+// omitted instead. A body that looks binary (a non-text Content-Type, or
+// bytes that aren't valid UTF-8) is replaced with a "[binary data, N
+// bytes]" placeholder rather than dumped to the terminal.
+func (r *HTTPResponse) Print(w io.Writer, opts PrintOptions) {
+	if !opts.BodyOnly {
+		fmt.Fprintf(w, "%s %s\n", r.Proto, r.Status)
+		printHeaders(w, r.Header, opts.AlignHeaders)
+		fmt.Fprintln(w)
+	}
+
+	if opts.HeadersOnly {
+		if opts.Timing {
+			fmt.Fprintf(w, "time: %s\n", r.Timing)
+		}
+		return
+	}
+
+	if r.isBinary() {
+		fmt.Fprintf(w, "[binary data, %d bytes]\n", len(r.RawBody))
+		if opts.Timing {
+			fmt.Fprintf(w, "time: %s\n", r.Timing)
+		}
+		return
+	}
+
+	body, trimMarker := trimBody(r.Body, opts.TrimBytes, opts.TrimLines)
+	if opts.Color {
+		t, ok := theme.Lookup(opts.Theme)
+		if !ok {
+			t = theme.Default
+		}
+		if colored, ok := colorizeJSON(body, t); ok {
+			body = colored
+		}
+	}
+	fmt.Fprint(w, body)
+	if r.Truncated {
+		fmt.Fprint(w, "...[truncated]")
+	}
+	if trimMarker != "" {
+		fmt.Fprint(w, trimMarker)
+	}
+	fmt.Fprintln(w)
+
+	if !opts.BodyOnly {
+		printHeaders(w, r.Trailer, opts.AlignHeaders)
+	}
+
+	if opts.Timing {
+		fmt.Fprintf(w, "time: %s\n", r.Timing)
+	}
+}
+
+// trimBody returns the prefix of body that Print should write, for
+// --trim-response/--trim-lines, along with the "[... N more
+// bytes/lines]" marker to append after it (or "" if body wasn't cut).
+// trimBytes and trimLines are mutually exclusive; a zero value disables
+// the corresponding limit.
+func trimBody(body string, trimBytes, trimLines int) (string, string) {
+	switch {
+	case trimBytes > 0 && len(body) > trimBytes:
+		return body[:trimBytes], fmt.Sprintf("[... %d more bytes]", len(body)-trimBytes)
+	case trimLines > 0:
+		lines := strings.Split(body, "\n")
+		if len(lines) > 0 && lines[len(lines)-1] == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) > trimLines {
+			kept := strings.Join(lines[:trimLines], "\n") + "\n"
+			return kept, fmt.Sprintf("[... %d more lines]", len(lines)-trimLines)
+		}
+	}
+	return body, ""
+}
+
+// isBinary reports whether r's body should be treated as binary: either
+// its Content-Type isn't a known text-ish type, or its bytes aren't
+// valid UTF-8.
+func (r *HTTPResponse) isBinary() bool {
+	if len(r.RawBody) == 0 {
+		return false
+	}
+	if ct := r.Header.Get("Content-Type"); ct != "" && !isTextContentType(ct) {
+		return true
+	}
+	return !utf8.Valid(r.RawBody)
+}
+
+// textContentTypeSuffixes names the "type/subtype" tokens (beyond the
+// "text/" prefix) that carry human-readable payloads worth printing
+// as-is: JSON, XML, and form/JS variants commonly seen in API traffic.
+var textContentTypeSuffixes = []string{
+	"json", "xml", "javascript", "x-www-form-urlencoded", "yaml", "graphql",
+}
+
+func isTextContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	if strings.HasPrefix(mediaType, "text/") {
+		return true
+	}
+	for _, suffix := range textContentTypeSuffixes {
+		if strings.HasSuffix(mediaType, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedHeaderNames(h http.Header) []string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// printHeaders writes h's headers to w, one "name: value" per line,
+// alphabetized by sortedHeaderNames. When align is set, every name is
+// padded to the width of the longest one so the colons line up in a
+// column; the padding is purely cosmetic and never reaches the wire.
+func printHeaders(w io.Writer, h http.Header, align bool) {
+	names := sortedHeaderNames(h)
+	width := 0
+	if align {
+		for _, name := range names {
+			if len(name) > width {
+				width = len(name)
+			}
+		}
+	}
+	for _, name := range names {
+		for _, value := range h[name] {
+			fmt.Fprintf(w, "%-*s: %s\n", width, name, value)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package models
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestPrint_BinaryContentTypeShowsPlaceholder(t *testing.T) {
+	png := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a}
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": {"image/png"}},
+		Body:       string(png),
+		RawBody:    png,
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{})
+
+	if !strings.Contains(buf.String(), "[binary data, 8 bytes]") {
+		t.Errorf("expected a binary placeholder, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "PNG") {
+		t.Errorf("did not expect raw binary bytes in output, got %q", buf.String())
+	}
+}
+
+func TestPrint_BodyOnlySuppressesStatusLineAndHeaders(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       `{"a":1}`,
+		RawBody:    []byte(`{"a":1}`),
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{BodyOnly: true})
+
+	if got := buf.String(); got != "{\"a\":1}\n" {
+		t.Errorf("expected just the body, got %q", got)
+	}
+}
+
+func TestPrint_HeadersOnlySuppressesBodyAndTrailer(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"ETag": {`"abc123"`}},
+		Body:       "hello",
+		RawBody:    []byte("hello"),
+		Trailer:    http.Header{"X-Checksum": {"deadbeef"}},
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{HeadersOnly: true})
+
+	got := buf.String()
+	if want := "HTTP/1.1 200 OK\nETag: \"abc123\"\n\n"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrint_MultipleSetCookieHeadersAllPrint(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Set-Cookie": {"session=abc", "theme=dark"}},
+		Body:       "",
+		RawBody:    []byte(""),
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{})
+
+	got := buf.String()
+	if !strings.Contains(got, "Set-Cookie: session=abc\n") {
+		t.Errorf("expected the first Set-Cookie header to print, got %q", got)
+	}
+	if !strings.Contains(got, "Set-Cookie: theme=dark\n") {
+		t.Errorf("expected the second Set-Cookie header to print, got %q", got)
+	}
+}
+
+func TestPrint_TrailerHeadersPrintAfterTheBody(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Transfer-Encoding": {"chunked"}},
+		Body:       "hello",
+		RawBody:    []byte("hello"),
+		Trailer:    http.Header{"X-Checksum": {"deadbeef"}},
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{})
+
+	if got := buf.String(); !strings.Contains(got, "hello\nX-Checksum: deadbeef\n") {
+		t.Errorf("expected the trailer to print after the body, got %q", got)
+	}
+}
+
+func TestPrint_BodyOnlySuppressesTrailerHeadersToo(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Body:       "hello",
+		RawBody:    []byte("hello"),
+		Trailer:    http.Header{"X-Checksum": {"deadbeef"}},
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{BodyOnly: true})
+
+	if got := buf.String(); got != "hello\n" {
+		t.Errorf("expected just the body, got %q", got)
+	}
+}
+
+func TestPrint_NonUTF8BodyShowsPlaceholder(t *testing.T) {
+	raw := []byte{0xff, 0xfe, 0x00, 0x01}
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{},
+		Body:       string(raw),
+		RawBody:    raw,
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{})
+
+	if !strings.Contains(buf.String(), "[binary data, 4 bytes]") {
+		t.Errorf("expected a binary placeholder, got %q", buf.String())
+	}
+}
+
+func TestPrint_JSONBodyPrintsNormally(t *testing.T) {
+	body := []byte(`{"ok":true}`)
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       string(body),
+		RawBody:    body,
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{})
+
+	if !strings.Contains(buf.String(), `{"ok":true}`) {
+		t.Errorf("expected the JSON body to print unchanged, got %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "binary data") {
+		t.Errorf("did not expect a binary placeholder for JSON, got %q", buf.String())
+	}
+}
+
+func TestPrint_TextBodyWithoutRawBodyStillPrints(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{},
+		Body:       "hello",
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{})
+
+	if !strings.Contains(buf.String(), "hello") {
+		t.Errorf("expected the body to print when RawBody is unset, got %q", buf.String())
+	}
+}
+
+func TestPrint_TrimBytesLimitsPrintedBody(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{},
+		Body:       "0123456789",
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{BodyOnly: true, TrimBytes: 4})
+
+	if got := buf.String(); got != "0123[... 6 more bytes]\n" {
+		t.Errorf("got %q", got)
+	}
+	if resp.Body != "0123456789" {
+		t.Errorf("expected Print to leave the in-memory body untouched, got %q", resp.Body)
+	}
+}
+
+func TestPrint_TrimLinesLimitsPrintedBody(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{},
+		Body:       "one\ntwo\nthree\nfour\n",
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{BodyOnly: true, TrimLines: 2})
+
+	if got := buf.String(); got != "one\ntwo\n[... 2 more lines]\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestPrint_TrimBytesDoesNothingWhenBodyIsShorter(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{},
+		Body:       "hi",
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{BodyOnly: true, TrimBytes: 100})
+
+	if got := buf.String(); got != "hi\n" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestPrint_HeadersAreAlphabetizedRegardlessOfAlignHeaders(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"X-Zebra": {"1"}, "Content-Type": {"text/plain"}, "Age": {"2"}},
+		Body:       "hi",
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{HeadersOnly: true})
+
+	want := "HTTP/1.1 200 OK\nAge: 2\nContent-Type: text/plain\nX-Zebra: 1\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestPrint_AlignHeadersPadsNamesToTheLongestColumn(t *testing.T) {
+	resp := &HTTPResponse{
+		Proto:      "HTTP/1.1",
+		StatusCode: 200,
+		Status:     "200 OK",
+		Header:     http.Header{"Age": {"2"}, "Content-Type": {"text/plain"}},
+		Body:       "hi",
+	}
+
+	var buf bytes.Buffer
+	resp.Print(&buf, PrintOptions{HeadersOnly: true, AlignHeaders: true})
+
+	want := "HTTP/1.1 200 OK\nAge         : 2\nContent-Type: text/plain\n\n"
+	if got := buf.String(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package ntlm
+
+import "encoding/binary"
+
+// md4Sum computes the MD4 message digest of data (RFC 1320), which
+// NTLMv2's NTOWFv2 key derivation needs to hash the password. Go's
+// standard library dropped MD4 along with the rest of the algorithm's
+// package; the only maintained implementation lives in
+// golang.org/x/crypto, which this project has no dependency on. Inputs
+// here are always short (a UTF-16LE password), so a one-shot function
+// is simpler than implementing the streaming hash.Hash interface.
+func md4Sum(data []byte) [16]byte {
+	state := [4]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476}
+
+	bitLen := uint64(len(data)) * 8
+	padded := make([]byte, 0, len(data)+72)
+	padded = append(padded, data...)
+	padded = append(padded, 0x80)
+	for len(padded)%64 != 56 {
+		padded = append(padded, 0)
+	}
+	var lengthField [8]byte
+	binary.LittleEndian.PutUint64(lengthField[:], bitLen)
+	padded = append(padded, lengthField[:]...)
+
+	for off := 0; off < len(padded); off += 64 {
+		md4Block(&state, padded[off:off+64])
+	}
+
+	var digest [16]byte
+	for i, word := range state {
+		binary.LittleEndian.PutUint32(digest[i*4:], word)
+	}
+	return digest
+}
+
+func leftRotate(x uint32, n uint) uint32 {
+	return (x << n) | (x >> (32 - n))
+}
+
+// md4Block runs the three MD4 rounds over a single 64-byte block,
+// updating state in place, per RFC 1320 section 3.4.
+func md4Block(state *[4]uint32, block []byte) {
+	var x [16]uint32
+	for i := range x {
+		x[i] = binary.LittleEndian.Uint32(block[i*4:])
+	}
+	a, b, c, d := state[0], state[1], state[2], state[3]
+
+	round1 := func(a, b, c, d, k uint32, s uint) uint32 {
+		f := (b & c) | (^b & d)
+		return leftRotate(a+f+x[k], s)
+	}
+	round2 := func(a, b, c, d, k uint32, s uint) uint32 {
+		g := (b & c) | (b & d) | (c & d)
+		return leftRotate(a+g+x[k]+0x5a827999, s)
+	}
+	round3 := func(a, b, c, d, k uint32, s uint) uint32 {
+		h := b ^ c ^ d
+		return leftRotate(a+h+x[k]+0x6ed9eba1, s)
+	}
+
+	shift1 := [4]uint{3, 7, 11, 19}
+	for i, k := range [16]uint32{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15} {
+		s := shift1[i%4]
+		switch i % 4 {
+		case 0:
+			a = round1(a, b, c, d, k, s)
+		case 1:
+			d = round1(d, a, b, c, k, s)
+		case 2:
+			c = round1(c, d, a, b, k, s)
+		case 3:
+			b = round1(b, c, d, a, k, s)
+		}
+	}
+
+	shift2 := [4]uint{3, 5, 9, 13}
+	order2 := [16]uint32{0, 4, 8, 12, 1, 5, 9, 13, 2, 6, 10, 14, 3, 7, 11, 15}
+	for i, k := range order2 {
+		s := shift2[i%4]
+		switch i % 4 {
+		case 0:
+			a = round2(a, b, c, d, k, s)
+		case 1:
+			d = round2(d, a, b, c, k, s)
+		case 2:
+			c = round2(c, d, a, b, k, s)
+		case 3:
+			b = round2(b, c, d, a, k, s)
+		}
+	}
+
+	shift3 := [4]uint{3, 9, 11, 15}
+	order3 := [16]uint32{0, 8, 4, 12, 2, 10, 6, 14, 1, 9, 5, 13, 3, 11, 7, 15}
+	for i, k := range order3 {
+		s := shift3[i%4]
+		switch i % 4 {
+		case 0:
+			a = round3(a, b, c, d, k, s)
+		case 1:
+			d = round3(d, a, b, c, k, s)
+		case 2:
+			c = round3(c, d, a, b, k, s)
+		case 3:
+			b = round3(b, c, d, a, k, s)
+		}
+	}
+
+	state[0] += a
+	state[1] += b
+	state[2] += c
+	state[3] += d
+}
@@ -0,0 +1,34 @@
+package ntlm
+
+import "testing"
+
+// RFC 1320 section A.5's published MD4 test vectors.
+func TestMd4Sum_RFC1320Vectors(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"", "31d6cfe0d16ae931b73c59d7e0c089c0"},
+		{"a", "bde52cb31de33e46245e05fbdbd6fb24"},
+		{"abc", "a448017aaf21d8525fc10ae87aa6729d"},
+		{"message digest", "d9130a8164549fe818874806e1c7014b"},
+		{"abcdefghijklmnopqrstuvwxyz", "d79e1c308aa5bbcdeea8ed63df412da9"},
+	}
+
+	for _, c := range cases {
+		got := md4Sum([]byte(c.input))
+		if hexEncode(got[:]) != c.want {
+			t.Errorf("md4Sum(%q) = %x, want %s", c.input, got, c.want)
+		}
+	}
+}
+
+func hexEncode(b []byte) string {
+	const digits = "0123456789abcdef"
+	out := make([]byte, len(b)*2)
+	for i, v := range b {
+		out[i*2] = digits[v>>4]
+		out[i*2+1] = digits[v&0x0f]
+	}
+	return string(out)
+}
@@ -0,0 +1,127 @@
+package ntlm
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+	"unicode/utf16"
+)
+
+func TestNegotiateMessage_HasExpectedHeader(t *testing.T) {
+	msg := NegotiateMessage()
+	if len(msg) != 32 {
+		t.Fatalf("expected a 32-byte Type 1 message, got %d bytes", len(msg))
+	}
+	if string(msg[0:8]) != signature {
+		t.Errorf("expected the NTLMSSP signature, got %q", msg[0:8])
+	}
+	if got := binary.LittleEndian.Uint32(msg[8:12]); got != 1 {
+		t.Errorf("expected message type 1, got %d", got)
+	}
+}
+
+// buildChallengeMessage constructs a synthetic NTLM Type 2 message for
+// tests, with the given server challenge and target info blob.
+func buildChallengeMessage(serverChallenge [8]byte, targetInfo []byte) []byte {
+	const headerLen = 48
+	msg := make([]byte, headerLen+len(targetInfo))
+	copy(msg[0:8], signature)
+	binary.LittleEndian.PutUint32(msg[8:12], 2)
+	copy(msg[24:32], serverChallenge[:])
+	binary.LittleEndian.PutUint16(msg[40:42], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint16(msg[42:44], uint16(len(targetInfo)))
+	binary.LittleEndian.PutUint32(msg[44:48], uint32(headerLen))
+	copy(msg[headerLen:], targetInfo)
+	return msg
+}
+
+func TestParseChallengeMessage_ExtractsServerChallengeAndTargetInfo(t *testing.T) {
+	serverChallenge := [8]byte{1, 2, 3, 4, 5, 6, 7, 8}
+	targetInfo := []byte{0xAA, 0xBB, 0xCC, 0xDD}
+
+	got, err := ParseChallengeMessage(buildChallengeMessage(serverChallenge, targetInfo))
+	if err != nil {
+		t.Fatalf("ParseChallengeMessage() error = %v", err)
+	}
+	if got.ServerChallenge != serverChallenge {
+		t.Errorf("ServerChallenge = %x, want %x", got.ServerChallenge, serverChallenge)
+	}
+	if string(got.TargetInfo) != string(targetInfo) {
+		t.Errorf("TargetInfo = %x, want %x", got.TargetInfo, targetInfo)
+	}
+}
+
+func TestParseChallengeMessage_RejectsWrongMessageType(t *testing.T) {
+	msg := NegotiateMessage() // a Type 1 message, not a Type 2
+	if _, err := ParseChallengeMessage(msg); err == nil {
+		t.Fatal("expected an error parsing a Type 1 message as a challenge")
+	}
+}
+
+func TestParseChallengeMessage_RejectsOutOfBoundsTargetInfo(t *testing.T) {
+	msg := buildChallengeMessage([8]byte{}, nil)
+	binary.LittleEndian.PutUint16(msg[40:42], 100) // claim far more target info than exists
+	if _, err := ParseChallengeMessage(msg); err == nil {
+		t.Fatal("expected an error for an out-of-bounds target info field")
+	}
+}
+
+func TestAuthenticateMessage_ProducesWellFormedType3(t *testing.T) {
+	challenge, err := ParseChallengeMessage(buildChallengeMessage([8]byte{9, 8, 7, 6, 5, 4, 3, 2}, []byte("targetinfo")))
+	if err != nil {
+		t.Fatalf("ParseChallengeMessage() error = %v", err)
+	}
+
+	msg, err := AuthenticateMessage(challenge, "EXAMPLE", "alice", "hunter2", time.Unix(0, 0))
+	if err != nil {
+		t.Fatalf("AuthenticateMessage() error = %v", err)
+	}
+
+	if string(msg[0:8]) != signature {
+		t.Fatalf("expected the NTLMSSP signature, got %q", msg[0:8])
+	}
+	if got := binary.LittleEndian.Uint32(msg[8:12]); got != 3 {
+		t.Fatalf("expected message type 3, got %d", got)
+	}
+
+	readField := func(byteOffset int) (length int, offset int) {
+		l := binary.LittleEndian.Uint16(msg[byteOffset : byteOffset+2])
+		o := binary.LittleEndian.Uint32(msg[byteOffset+4 : byteOffset+8])
+		return int(l), int(o)
+	}
+
+	ntLen, ntOffset := readField(20)
+	if ntLen < 16 {
+		t.Fatalf("expected an NTChallengeResponse of at least 16 bytes (the NTProofStr), got %d", ntLen)
+	}
+	if ntOffset+ntLen > len(msg) {
+		t.Fatalf("NtChallengeResponse field (offset %d, len %d) overruns the %d-byte message", ntOffset, ntLen, len(msg))
+	}
+
+	userLen, userOffset := readField(36)
+	gotUser := utf16Decode(msg[userOffset : userOffset+userLen])
+	if gotUser != "alice" {
+		t.Errorf("username in Type 3 payload = %q, want %q", gotUser, "alice")
+	}
+
+	domainLen, domainOffset := readField(28)
+	gotDomain := utf16Decode(msg[domainOffset : domainOffset+domainLen])
+	if gotDomain != "EXAMPLE" {
+		t.Errorf("domain in Type 3 payload = %q, want %q", gotDomain, "EXAMPLE")
+	}
+}
+
+func TestAuthenticateMessage_RequiresUsername(t *testing.T) {
+	challenge, _ := ParseChallengeMessage(buildChallengeMessage([8]byte{}, nil))
+	if _, err := AuthenticateMessage(challenge, "", "", "pw", time.Now()); err == nil {
+		t.Fatal("expected an error for an empty username")
+	}
+}
+
+func utf16Decode(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(b[i*2:])
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,248 @@
+// Package openapi builds a request skeleton from an OpenAPI 3.0
+// document: given an operationId, it locates the operation's method and
+// path, fills in path/query parameters from caller-supplied values, and
+// sketches a body from the request schema.
+//
+// Only the JSON encoding of an OpenAPI 3.0 document is supported. YAML
+// specs need a YAML parser, which this dependency-free tool doesn't
+// carry; convert the spec to JSON first (most OpenAPI tooling, and
+// `yq . spec.yaml -o=json`, can do this).
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// Spec is the subset of an OpenAPI 3.0 document this package
+// understands: enough to locate an operation and build a request for
+// it.
+type Spec struct {
+	Servers []Server            `json:"servers"`
+	Paths   map[string]PathItem `json:"paths"`
+}
+
+// Server is one entry in the document's top-level "servers" list. Only
+// the first is used; OpenAPI's per-operation server overrides and
+// variable substitution aren't supported.
+type Server struct {
+	URL string `json:"url"`
+}
+
+// PathItem holds the operations declared on one path, keyed by the
+// lower-case HTTP method OpenAPI itself uses ("get", "post", ...).
+type PathItem map[string]Operation
+
+// Operation is a single method+path combination.
+type Operation struct {
+	OperationID string       `json:"operationId"`
+	Parameters  []Parameter  `json:"parameters"`
+	RequestBody *RequestBody `json:"requestBody"`
+}
+
+// Parameter is a path or query parameter declared on an operation.
+type Parameter struct {
+	Name     string `json:"name"`
+	In       string `json:"in"` // "path" or "query"; others are ignored
+	Required bool   `json:"required"`
+}
+
+// RequestBody is an operation's declared request body, keyed by media
+// type ("application/json", ...).
+type RequestBody struct {
+	Content map[string]MediaType `json:"content"`
+}
+
+// MediaType pairs a request body's content type with the schema of the
+// bytes it carries.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Schema is the subset of JSON Schema OpenAPI embeds that's needed to
+// sketch an example value: its type, and for an object or array, the
+// schema of what it contains.
+type Schema struct {
+	Type       string             `json:"type"`
+	Properties map[string]*Schema `json:"properties"`
+	Items      *Schema            `json:"items"`
+}
+
+// LoadSpec reads and parses the OpenAPI document at path.
+func LoadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: %w", err)
+	}
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+			return nil, fmt.Errorf("openapi: %s looks like YAML, but only the JSON encoding of OpenAPI 3.0 is supported: %w", path, err)
+		}
+		return nil, fmt.Errorf("openapi: parsing %s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// FindOperation returns the method, path, and Operation whose
+// operationId matches id. Paths are searched in sorted order and each
+// path's methods in sorted order, so the result is deterministic if a
+// spec ever declares the same operationId twice.
+func FindOperation(spec *Spec, id string) (method, path string, op Operation, err error) {
+	paths := make([]string, 0, len(spec.Paths))
+	for p := range spec.Paths {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		methods := make([]string, 0, len(spec.Paths[p]))
+		for m := range spec.Paths[p] {
+			methods = append(methods, m)
+		}
+		sort.Strings(methods)
+
+		for _, m := range methods {
+			candidate := spec.Paths[p][m]
+			if candidate.OperationID == id {
+				return m, p, candidate, nil
+			}
+		}
+	}
+	return "", "", Operation{}, fmt.Errorf("openapi: no operation named %q", id)
+}
+
+// BuildRequest constructs the request for the operation named id:
+// method and path from the spec, the URL's host from the first server
+// entry, path/query parameters filled from params, and (if the
+// operation declares one) a request body sketched from its schema.
+// params is keyed by parameter name; a required parameter missing from
+// params is an error.
+func BuildRequest(spec *Spec, id string, params map[string]string) (*models.Request, error) {
+	method, path, op, err := FindOperation(spec, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range op.Parameters {
+		if p.In != "path" {
+			continue
+		}
+		value, ok := params[p.Name]
+		if !ok {
+			if p.Required {
+				return nil, fmt.Errorf("openapi: operation %q requires path parameter %q", id, p.Name)
+			}
+			continue
+		}
+		path = strings.ReplaceAll(path, "{"+p.Name+"}", value)
+	}
+
+	base := ""
+	if len(spec.Servers) > 0 {
+		base = strings.TrimRight(spec.Servers[0].URL, "/")
+	}
+	target := base + path
+
+	var query []string
+	for _, p := range op.Parameters {
+		if p.In != "query" {
+			continue
+		}
+		value, ok := params[p.Name]
+		if !ok {
+			if p.Required {
+				return nil, fmt.Errorf("openapi: operation %q requires query parameter %q", id, p.Name)
+			}
+			continue
+		}
+		query = append(query, p.Name+"="+value)
+	}
+	if len(query) > 0 {
+		target += "?" + strings.Join(query, "&")
+	}
+
+	req := models.New()
+	req.Method = strings.ToUpper(method)
+	req.RawTarget = target
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("openapi: operation %q built an invalid target %q: %w", id, target, err)
+	}
+	req.URL = u
+
+	if op.RequestBody != nil {
+		contentType, mediaType := chooseContentType(op.RequestBody.Content)
+		if contentType != "" {
+			body, err := json.MarshalIndent(example(mediaType.Schema, 0), "", "  ")
+			if err != nil {
+				return nil, fmt.Errorf("openapi: operation %q: sketching request body: %w", id, err)
+			}
+			req.Body = string(body)
+			req.SetHeader("Content-Type", contentType)
+		}
+	}
+
+	return req, nil
+}
+
+// chooseContentType prefers "application/json" when present, since
+// that's what BuildRequest's caller almost always wants a skeleton for;
+// otherwise it falls back to whichever content type sorts first, for a
+// deterministic result.
+func chooseContentType(content map[string]MediaType) (string, MediaType) {
+	if mt, ok := content["application/json"]; ok {
+		return "application/json", mt
+	}
+	types := make([]string, 0, len(content))
+	for t := range content {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	if len(types) == 0 {
+		return "", MediaType{}
+	}
+	return types[0], content[types[0]]
+}
+
+// example builds a placeholder value for schema: zero values for
+// scalars, a single-element slice for an array, and a fully-populated
+// object for an object, so the caller can see every field's shape at a
+// glance. depth guards against a schema that references itself.
+func example(schema *Schema, depth int) any {
+	if schema == nil || depth > 10 {
+		return nil
+	}
+	switch schema.Type {
+	case "object":
+		names := make([]string, 0, len(schema.Properties))
+		for name := range schema.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		obj := make(map[string]any, len(names))
+		for _, name := range names {
+			obj[name] = example(schema.Properties[name], depth+1)
+		}
+		return obj
+	case "array":
+		return []any{example(schema.Items, depth+1)}
+	case "string":
+		return ""
+	case "integer":
+		return 0
+	case "number":
+		return 0
+	case "boolean":
+		return false
+	default:
+		return nil
+	}
+}
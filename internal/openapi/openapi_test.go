@@ -0,0 +1,128 @@
+package openapi
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSpec = `{
+  "openapi": "3.0.0",
+  "servers": [{"url": "https://api.example.com/v1"}],
+  "paths": {
+    "/users/{id}": {
+      "get": {
+        "operationId": "getUser",
+        "parameters": [
+          {"name": "id", "in": "path", "required": true},
+          {"name": "verbose", "in": "query", "required": false}
+        ]
+      },
+      "post": {
+        "operationId": "updateUser",
+        "parameters": [
+          {"name": "id", "in": "path", "required": true}
+        ],
+        "requestBody": {
+          "content": {
+            "application/json": {
+              "schema": {
+                "type": "object",
+                "properties": {
+                  "name": {"type": "string"},
+                  "age": {"type": "integer"},
+                  "tags": {"type": "array", "items": {"type": "string"}}
+                }
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+func writeTestSpec(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "spec.json")
+	if err := os.WriteFile(path, []byte(testSpec), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestBuildRequest_FillsPathAndQueryParameters(t *testing.T) {
+	spec, err := LoadSpec(writeTestSpec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := BuildRequest(spec, "getUser", map[string]string{"id": "42", "verbose": "true"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "GET" {
+		t.Errorf("expected method GET, got %q", req.Method)
+	}
+	if req.URL.String() != "https://api.example.com/v1/users/42?verbose=true" {
+		t.Errorf("unexpected URL: %s", req.URL)
+	}
+}
+
+func TestBuildRequest_MissingRequiredPathParameterErrors(t *testing.T) {
+	spec, err := LoadSpec(writeTestSpec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := BuildRequest(spec, "getUser", nil); err == nil {
+		t.Fatal("expected an error for a missing required path parameter")
+	}
+}
+
+func TestBuildRequest_SketchesBodyFromRequestSchema(t *testing.T) {
+	spec, err := LoadSpec(writeTestSpec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := BuildRequest(spec, "updateUser", map[string]string{"id": "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("expected Content-Type application/json, got %q", req.Header.Get("Content-Type"))
+	}
+	for _, field := range []string{`"name": ""`, `"age": 0`, `"tags":`} {
+		if !strings.Contains(req.Body, field) {
+			t.Errorf("expected the body sketch to contain %q, got %s", field, req.Body)
+		}
+	}
+}
+
+func TestBuildRequest_UnknownOperationErrors(t *testing.T) {
+	spec, err := LoadSpec(writeTestSpec(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := BuildRequest(spec, "doesNotExist", nil); err == nil {
+		t.Fatal("expected an error for an unknown operationId")
+	}
+}
+
+func TestLoadSpec_YAMLExtensionGetsAClearError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yaml")
+	if err := os.WriteFile(path, []byte("openapi: 3.0.0\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadSpec(path)
+	if err == nil {
+		t.Fatal("expected an error for a YAML spec")
+	}
+	if !strings.Contains(err.Error(), "YAML") {
+		t.Errorf("expected the error to mention YAML isn't supported, got %v", err)
+	}
+}
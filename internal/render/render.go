@@ -0,0 +1,86 @@
+// Package render prints requests and responses to an io.Writer in the
+// plain-text format the CLI shows on stdout.
+package render
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/adamijak/http/internal/bench"
+	"github.com/adamijak/http/internal/models"
+)
+
+// Request prints req the way it would appear on the wire, without
+// sending it. Used by --no-send. When absoluteForm is true (a proxy is
+// configured) the request-target is the full absolute URI instead of
+// origin-form path+query, matching what the proxy will actually see.
+// headerCase controls header name casing, matching --header-case; see
+// models.Request.WireHeaders.
+func Request(w io.Writer, req *models.Request, absoluteForm bool, headerCase string) {
+	target := req.URL.RequestURI()
+	switch {
+	case req.IsConnect():
+		// CONNECT's target is just the "host:port" authority; RequestURI
+		// would otherwise default to "/" since there's no path at all.
+		target = req.URL.Host
+	case absoluteForm:
+		target = req.URL.String()
+	}
+	fmt.Fprintf(w, "%s %s %s\n", req.Method, target, req.Proto)
+	headers := req.WireHeaders(headerCase)
+	for _, name := range sortedHeaderNames(headers) {
+		for _, value := range headers[name] {
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+	if req.Body != "" {
+		fmt.Fprintln(w)
+		if isBinaryBody(req) {
+			fmt.Fprintf(w, "[binary data, %d bytes]\n", len(req.Body))
+		} else {
+			fmt.Fprintln(w, req.Body)
+		}
+	}
+}
+
+// isBinaryBody reports whether req's body shouldn't be dumped as text:
+// either it's Content-Encoding'd (e.g. --compress gzip), or its bytes
+// aren't valid UTF-8.
+func isBinaryBody(req *models.Request) bool {
+	if req.Header.Get("Content-Encoding") != "" {
+		return true
+	}
+	return !utf8.ValidString(req.Body)
+}
+
+// Stats prints the aggregate result of a --repeat benchmark run: a
+// count/errors line, then min/avg/max/p95 latency, then a per-status-code
+// histogram.
+func Stats(w io.Writer, s *bench.Stats) {
+	fmt.Fprintf(w, "count: %d, errors: %d\n", s.Count, s.Errors)
+	fmt.Fprintf(w, "min: %s, avg: %s, max: %s, p95: %s\n", s.Min, s.Avg, s.Max, s.P95)
+	fmt.Fprintln(w, "status codes:")
+	for _, code := range sortedStatusCodes(s.StatusCodes) {
+		fmt.Fprintf(w, "  %d: %d\n", code, s.StatusCodes[code])
+	}
+}
+
+func sortedStatusCodes(m map[int]int) []int {
+	codes := make([]int, 0, len(m))
+	for code := range m {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	return codes
+}
+
+func sortedHeaderNames(h map[string][]string) []string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
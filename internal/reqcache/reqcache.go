@@ -0,0 +1,75 @@
+// Package reqcache implements a small, opt-in on-disk cache of
+// conditional-GET validators (ETag/Last-Modified) and response bodies
+// for the --cache-dir flag, so polling the same URL repeatedly can send
+// If-None-Match/If-Modified-Since and skip re-downloading unchanged
+// responses.
+package reqcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Entry is what's cached for one URL: the validators needed to make a
+// conditional request, and the body/status to reuse on a 304.
+type Entry struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	StatusCode   int    `json:"status_code"`
+	Body         string `json:"body"`
+}
+
+// Cache reads and writes Entry files under a directory, one per cached
+// URL. It holds no in-memory state of its own so concurrent CLI
+// invocations sharing the same --cache-dir see each other's writes.
+type Cache struct {
+	dir string
+}
+
+// Open returns a Cache rooted at dir. dir is created on first Put, not
+// here, so a --cache-dir that's never actually used (e.g. every request
+// misses validation) doesn't leave behind an empty directory.
+func Open(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Get returns the cached entry for u, or nil if there isn't one. A
+// missing or corrupt cache file is treated as a miss rather than an
+// error, since a stale/damaged entry should never block sending the
+// request it would have short-circuited.
+func (c *Cache) Get(u *url.URL) *Entry {
+	data, err := os.ReadFile(c.path(u))
+	if err != nil {
+		return nil
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil
+	}
+	return &e
+}
+
+// Put stores e as the cached entry for u, creating the cache directory
+// if needed.
+func (c *Cache) Put(u *url.URL, e *Entry) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path(u), data, 0o600)
+}
+
+// path returns the cache file for u: its URL, hashed, so arbitrary
+// URLs (including ones with characters unsafe in a filename) map to a
+// fixed-shape path within dir.
+func (c *Cache) path(u *url.URL) string {
+	sum := sha256.Sum256([]byte(u.String()))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
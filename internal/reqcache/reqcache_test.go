@@ -0,0 +1,61 @@
+package reqcache
+
+import (
+	"net/url"
+	"path/filepath"
+	"testing"
+)
+
+func TestCache_PutAndGetRoundTrip(t *testing.T) {
+	u, _ := url.Parse("https://example.com/status")
+	c := Open(t.TempDir())
+
+	want := &Entry{ETag: `"abc"`, StatusCode: 200, Body: "hello"}
+	if err := c.Put(u, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got := c.Get(u)
+	if got == nil || got.ETag != want.ETag || got.Body != want.Body {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestCache_GetMissReturnsNil(t *testing.T) {
+	u, _ := url.Parse("https://example.com/never-cached")
+	c := Open(t.TempDir())
+
+	if got := c.Get(u); got != nil {
+		t.Fatalf("expected a miss, got %+v", got)
+	}
+}
+
+func TestCache_DistinctURLsDontCollide(t *testing.T) {
+	dir := t.TempDir()
+	c := Open(dir)
+
+	a, _ := url.Parse("https://example.com/a")
+	b, _ := url.Parse("https://example.com/b")
+	c.Put(a, &Entry{Body: "a"})
+	c.Put(b, &Entry{Body: "b"})
+
+	if got := c.Get(a); got.Body != "a" {
+		t.Errorf("expected %q, got %+v", "a", got)
+	}
+	if got := c.Get(b); got.Body != "b" {
+		t.Errorf("expected %q, got %+v", "b", got)
+	}
+}
+
+func TestCache_PutCreatesMissingDirectory(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested", "cache-dir")
+	c := Open(dir)
+
+	u, _ := url.Parse("https://example.com/")
+	if err := c.Put(u, &Entry{Body: "x"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Get(u); got == nil || got.Body != "x" {
+		t.Fatalf("expected the entry to round-trip, got %+v", got)
+	}
+}
@@ -0,0 +1,73 @@
+// Package reqjson builds a *models.Request from a JSON document, as a
+// machine-friendly alternative to the .http text format for tooling
+// that generates requests programmatically rather than writing them by
+// hand.
+//
+// Only JSON is supported. YAML would need a YAML parser, which this
+// dependency-free tool doesn't carry; convert the document to JSON
+// first (most YAML tooling, and `yq . request.yaml -o=json`, can do
+// this).
+package reqjson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/adamijak/http/internal/httpfile"
+	"github.com/adamijak/http/internal/models"
+)
+
+// document is the on-the-wire shape Parse accepts. The decoder rejects
+// unknown fields so a typo (e.g. "hedaers") is a clear error instead of
+// a silently ignored one.
+type document struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Version string            `json:"version"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
+
+// Parse reads a single JSON request definition from r and builds the
+// equivalent *models.Request, bypassing httpfile.Parse entirely. Method
+// defaults to GET when omitted; version defaults to "HTTP/1.1", the
+// same default parseRequestLine uses for a two-field .http request
+// line.
+func Parse(r io.Reader) (*models.Request, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	var doc document
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("reqjson: %w", err)
+	}
+	if doc.URL == "" {
+		return nil, fmt.Errorf("reqjson: missing required field %q", "url")
+	}
+
+	method := doc.Method
+	if method == "" {
+		method = "GET"
+	}
+	version := doc.Version
+	if version == "" {
+		version = "HTTP/1.1"
+	}
+
+	u, err := httpfile.ParseTarget(method, doc.URL)
+	if err != nil {
+		return nil, fmt.Errorf("reqjson: invalid url %q: %w", doc.URL, err)
+	}
+
+	req := models.New()
+	req.Method = method
+	req.URL = u
+	req.RawTarget = doc.URL
+	req.Proto = version
+	req.Body = doc.Body
+	for name, value := range doc.Headers {
+		req.SetHeader(name, value)
+	}
+	return req, nil
+}
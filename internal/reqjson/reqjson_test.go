@@ -0,0 +1,60 @@
+package reqjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse_BuildsRequestFromDocument(t *testing.T) {
+	doc := `{"method":"POST","url":"http://example.invalid/widgets","version":"HTTP/1.1","headers":{"X-Test":"value"},"body":"hello"}`
+
+	req, err := Parse(strings.NewReader(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "POST" {
+		t.Errorf("expected method POST, got %q", req.Method)
+	}
+	if req.URL.String() != "http://example.invalid/widgets" {
+		t.Errorf("expected the parsed url, got %q", req.URL.String())
+	}
+	if req.Proto != "HTTP/1.1" {
+		t.Errorf("expected proto HTTP/1.1, got %q", req.Proto)
+	}
+	if req.Header.Get("X-Test") != "value" {
+		t.Errorf("expected header value %q, got %q", "value", req.Header.Get("X-Test"))
+	}
+	if req.Body != "hello" {
+		t.Errorf("expected body %q, got %q", "hello", req.Body)
+	}
+}
+
+func TestParse_DefaultsMethodAndVersion(t *testing.T) {
+	req, err := Parse(strings.NewReader(`{"url":"http://example.invalid/"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.Method != "GET" {
+		t.Errorf("expected default method GET, got %q", req.Method)
+	}
+	if req.Proto != "HTTP/1.1" {
+		t.Errorf("expected default proto HTTP/1.1, got %q", req.Proto)
+	}
+}
+
+func TestParse_MissingURLIsAnError(t *testing.T) {
+	_, err := Parse(strings.NewReader(`{"method":"GET"}`))
+	if err == nil {
+		t.Fatal("expected an error for a missing url")
+	}
+}
+
+func TestParse_UnknownFieldIsAnError(t *testing.T) {
+	_, err := Parse(strings.NewReader(`{"url":"http://example.invalid/","hedaers":{}}`))
+	if err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+	if !strings.Contains(err.Error(), "hedaers") {
+		t.Errorf("expected the error to name the unknown field, got %q", err.Error())
+	}
+}
@@ -0,0 +1,14 @@
+// Package sign implements request-signing schemes that compute
+// authentication headers (e.g. Authorization) from a request's final
+// method, URL, headers, and body.
+package sign
+
+import "github.com/adamijak/http/internal/models"
+
+// Signer mutates req in place to add whatever authentication data its
+// scheme computes. It must run last, after query parameters, form
+// bodies, and "{{var}}" substitution have all been applied, since every
+// signing scheme signs over the exact bytes that will go on the wire.
+type Signer interface {
+	Sign(req *models.Request) error
+}
@@ -0,0 +1,112 @@
+package sign
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/adamijak/http/internal/models"
+)
+
+// fixedClock reproduces AWS's published "get-vanilla" SigV4 test-suite
+// vector: a bare GET to a service with no query string or body, signed
+// at a fixed instant against the well-known AKIDEXAMPLE test
+// credentials.
+func fixedClock(t time.Time) func() time.Time {
+	return func() time.Time { return t }
+}
+
+func TestAWSSigV4_SignMatchesGetVanillaTestVector(t *testing.T) {
+	u, err := url.Parse("http://example.amazonaws.com/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := models.New()
+	req.Method = "GET"
+	req.URL = u
+	req.SetHeader("Host", "example.amazonaws.com")
+
+	signer := AWSSigV4{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY",
+		Region:          "us-east-1",
+		Service:         "service",
+		Clock:           fixedClock(time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)),
+	}
+
+	if err := signer.Sign(req); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("X-Amz-Date = %q, want %q", got, "20150830T123600Z")
+	}
+
+	want := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=ea21d6f05e96a897f6000a1a293f0a5bf0f92a00343409e820dce329ca6365ea"
+	if got := req.Header.Get("Authorization"); got != want {
+		t.Errorf("Authorization = %q, want %q", got, want)
+	}
+}
+
+func TestAWSSigV4_SignAddsSecurityTokenHeaderForTemporaryCredentials(t *testing.T) {
+	u, _ := url.Parse("http://example.amazonaws.com/")
+	req := models.New()
+	req.Method = "GET"
+	req.URL = u
+	req.SetHeader("Host", "example.amazonaws.com")
+
+	signer := AWSSigV4{
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY",
+		SessionToken:    "sts-session-token",
+		Region:          "us-east-1",
+		Service:         "service",
+		Clock:           fixedClock(time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)),
+	}
+
+	if err := signer.Sign(req); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Header.Get("X-Amz-Security-Token"); got != "sts-session-token" {
+		t.Errorf("X-Amz-Security-Token = %q, want %q", got, "sts-session-token")
+	}
+	// The session token header is added before signing, so it's signed
+	// along with the rest of the headers.
+	if got := req.Header.Get("Authorization"); got == "" {
+		t.Fatal("expected an Authorization header to still be set")
+	}
+}
+
+func TestAWSSigV4_SignFailsWithoutCredentials(t *testing.T) {
+	u, _ := url.Parse("http://example.amazonaws.com/")
+	req := models.New()
+	req.Method = "GET"
+	req.URL = u
+
+	signer := AWSSigV4{Region: "us-east-1", Service: "service"}
+	if err := signer.Sign(req); err == nil {
+		t.Fatal("expected an error signing without credentials")
+	}
+}
+
+func TestCanonicalQueryString_SortsParametersByNameThenValue(t *testing.T) {
+	u, err := url.Parse("https://example.com/?b=2&a=2&a=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := canonicalQueryString(u)
+	want := "a=1&a=2&b=2"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestSigV4Encode_LeavesUnreservedCharactersAlone(t *testing.T) {
+	got := sigV4Encode("abc123-._~ /")
+	want := "abc123-._~%20%2F"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
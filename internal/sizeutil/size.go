@@ -0,0 +1,47 @@
+// Package sizeutil parses human-readable byte sizes like "10MB" used by
+// flags such as --max-body-size.
+package sizeutil
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var units = map[string]int64{
+	"":   1,
+	"b":  1,
+	"k":  1 << 10,
+	"kb": 1 << 10,
+	"m":  1 << 20,
+	"mb": 1 << 20,
+	"g":  1 << 30,
+	"gb": 1 << 30,
+}
+
+// Parse converts a size string such as "10MB", "512k", or a plain byte
+// count ("0" meaning unlimited) into a number of bytes.
+func Parse(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("sizeutil: empty size")
+	}
+
+	i := 0
+	for i < len(s) && (s[i] == '.' || (s[i] >= '0' && s[i] <= '9')) {
+		i++
+	}
+	numPart, unitPart := s[:i], strings.ToLower(strings.TrimSpace(s[i:]))
+
+	n, err := strconv.ParseFloat(numPart, 64)
+	if err != nil {
+		return 0, fmt.Errorf("sizeutil: invalid size %q: %w", s, err)
+	}
+
+	mult, ok := units[unitPart]
+	if !ok {
+		return 0, fmt.Errorf("sizeutil: unknown unit %q in %q", unitPart, s)
+	}
+
+	return int64(n * float64(mult)), nil
+}
@@ -0,0 +1,30 @@
+package sizeutil
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	cases := map[string]int64{
+		"0":    0,
+		"1024": 1024,
+		"10MB": 10 * 1 << 20,
+		"512k": 512 * 1 << 10,
+		"1.5g": int64(1.5 * (1 << 30)),
+		"2 MB": 2 * 1 << 20,
+	}
+	for input, want := range cases {
+		got, err := Parse(input)
+		if err != nil {
+			t.Errorf("Parse(%q): unexpected error: %v", input, err)
+			continue
+		}
+		if got != want {
+			t.Errorf("Parse(%q) = %d, want %d", input, got, want)
+		}
+	}
+}
+
+func TestParse_InvalidUnit(t *testing.T) {
+	if _, err := Parse("10XB"); err == nil {
+		t.Error("expected error for unknown unit")
+	}
+}
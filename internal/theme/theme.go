@@ -0,0 +1,56 @@
+// Package theme centralizes the ANSI escape codes used for --color
+// syntax highlighting, so a palette can be swapped out (e.g. for a
+// light terminal) without hunting through the packages that print
+// colored output.
+package theme
+
+// Theme names the ANSI escape codes used to highlight a JSON response
+// body: keys, string values, numbers, true/false/null, and punctuation,
+// plus the code that resets back to the terminal's default.
+type Theme struct {
+	Reset   string
+	Key     string
+	String  string
+	Number  string
+	Literal string
+	Punct   string
+}
+
+// Default is the tool's original palette, chosen to match the jq
+// defaults developers are already used to.
+var Default = Theme{
+	Reset:   "\x1b[0m",
+	Key:     "\x1b[36m", // cyan
+	String:  "\x1b[32m", // green
+	Number:  "\x1b[33m", // yellow
+	Literal: "\x1b[35m", // magenta
+	Punct:   "\x1b[2m",  // dim
+}
+
+// Light swaps out the colors that read poorly on a light background
+// (cyan and dim white in particular) for darker equivalents.
+var Light = Theme{
+	Reset:   "\x1b[0m",
+	Key:     "\x1b[34m", // blue
+	String:  "\x1b[32m", // green
+	Number:  "\x1b[31m", // red
+	Literal: "\x1b[35m", // magenta
+	Punct:   "\x1b[30m", // black
+}
+
+var themes = map[string]Theme{
+	"default": Default,
+	"light":   Light,
+}
+
+// Names lists the themes selectable via --theme, in a stable order.
+func Names() []string {
+	return []string{"default", "light"}
+}
+
+// Lookup returns the theme registered under name and whether it was
+// found.
+func Lookup(name string) (Theme, bool) {
+	t, ok := themes[name]
+	return t, ok
+}
@@ -0,0 +1,24 @@
+package theme
+
+import "testing"
+
+func TestLookup_KnownNamesReturnDistinctThemes(t *testing.T) {
+	for _, name := range Names() {
+		got, ok := Lookup(name)
+		if !ok {
+			t.Fatalf("Lookup(%q) reported not found", name)
+		}
+		if got.Reset == "" {
+			t.Errorf("Lookup(%q) returned a theme with an empty Reset code", name)
+		}
+	}
+	if Default == Light {
+		t.Error("Default and Light must not be identical")
+	}
+}
+
+func TestLookup_UnknownNameReportsNotFound(t *testing.T) {
+	if _, ok := Lookup("nonexistent"); ok {
+		t.Error(`Lookup("nonexistent") reported found, want not found`)
+	}
+}
@@ -0,0 +1,66 @@
+package validate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// pathAllowedExtra and queryAllowedExtra list the RFC 3986 characters,
+// beyond ASCII letters and digits, that are allowed unencoded in a path
+// or query respectively without needing percent-encoding.
+const (
+	pathAllowedExtra  = "-._~!$&'()*+,;=:@/"
+	queryAllowedExtra = "-._~!$&'()*+,;=:@/?"
+)
+
+// needsEncoding reports whether s contains a byte outside allowed that
+// isn't already part of a valid "%XX" escape sequence.
+func needsEncoding(s, allowed string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			i += 2
+			continue
+		}
+		if !isAllowedByte(c, allowed) {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeUnsafe percent-encodes every byte in s not in allowed, leaving
+// already-valid "%XX" escape sequences untouched so it never
+// double-encodes.
+func encodeUnsafe(s, allowed string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]) {
+			b.WriteByte(c)
+			b.WriteByte(s[i+1])
+			b.WriteByte(s[i+2])
+			i += 2
+			continue
+		}
+		if isAllowedByte(c, allowed) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func isAllowedByte(c byte, extra string) bool {
+	switch {
+	case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		return true
+	default:
+		return strings.IndexByte(extra, c) >= 0
+	}
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
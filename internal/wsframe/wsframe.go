@@ -0,0 +1,134 @@
+// Package wsframe implements the WebSocket frame format from RFC 6455
+// §5.2: reading and writing the individual frames a --websocket session
+// exchanges once the HTTP upgrade handshake has completed. It doesn't
+// handle the handshake itself, fragmentation reassembly, or extensions
+// (compression, etc.) — just single-frame text/binary/control messages,
+// which is all a simple send/receive loop needs.
+package wsframe
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Opcode identifies a frame's payload type, per RFC 6455 §11.8.
+type Opcode byte
+
+const (
+	OpContinuation Opcode = 0x0
+	OpText         Opcode = 0x1
+	OpBinary       Opcode = 0x2
+	OpClose        Opcode = 0x8
+	OpPing         Opcode = 0x9
+	OpPong         Opcode = 0xA
+)
+
+// Frame is a single, unfragmented WebSocket frame with the FIN bit set.
+type Frame struct {
+	Opcode  Opcode
+	Payload []byte
+}
+
+// WriteMasked writes f to w with a client-to-server frame's mandatory
+// random masking key (RFC 6455 §5.1: "a client MUST mask all frames").
+func WriteMasked(w io.Writer, f Frame) error {
+	return writeFrame(w, f, true)
+}
+
+// WriteUnmasked writes f to w without a masking key, as a server sends
+// it (RFC 6455 §5.1: "a server MUST NOT mask any frames").
+func WriteUnmasked(w io.Writer, f Frame) error {
+	return writeFrame(w, f, false)
+}
+
+func writeFrame(w io.Writer, f Frame, masked bool) error {
+	var header []byte
+	header = append(header, 0x80|byte(f.Opcode)) // FIN=1, no extension bits
+
+	length := len(f.Payload)
+	maskBit := byte(0)
+	if masked {
+		maskBit = 0x80
+	}
+	switch {
+	case length < 126:
+		header = append(header, maskBit|byte(length))
+	case length <= 0xFFFF:
+		header = append(header, maskBit|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, maskBit|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if !masked {
+		_, err := w.Write(f.Payload)
+		return err
+	}
+
+	var key [4]byte
+	if _, err := rand.Read(key[:]); err != nil {
+		return fmt.Errorf("wsframe: generating mask key: %w", err)
+	}
+	if _, err := w.Write(key[:]); err != nil {
+		return err
+	}
+	maskedPayload := make([]byte, len(f.Payload))
+	for i, b := range f.Payload {
+		maskedPayload[i] = b ^ key[i%4]
+	}
+	_, err := w.Write(maskedPayload)
+	return err
+}
+
+// Read reads and unmasks (if masked) a single frame from r. It doesn't
+// reassemble fragmented messages (RFC 6455 §5.4); a fragmented frame's
+// payload is returned as-is, with the caller left to notice the FIN bit
+// isn't reflected here and handle it if that ever matters.
+func Read(r io.Reader) (Frame, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return Frame{}, err
+	}
+	opcode := Opcode(head[0] & 0x0F)
+	masked := head[1]&0x80 != 0
+	length := uint64(head[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return Frame{}, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return Frame{}, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+
+	var key [4]byte
+	if masked {
+		if _, err := io.ReadFull(r, key[:]); err != nil {
+			return Frame{}, err
+		}
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return Frame{}, err
+	}
+	if masked {
+		for i, b := range payload {
+			payload[i] = b ^ key[i%4]
+		}
+	}
+	return Frame{Opcode: opcode, Payload: payload}, nil
+}
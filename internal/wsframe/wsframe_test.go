@@ -0,0 +1,75 @@
+package wsframe
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteMasked_RoundTripsThroughRead(t *testing.T) {
+	var buf bytes.Buffer
+	want := Frame{Opcode: OpText, Payload: []byte("hello")}
+	if err := WriteMasked(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Opcode != want.Opcode || !bytes.Equal(got.Payload, want.Payload) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteUnmasked_RoundTripsThroughRead(t *testing.T) {
+	var buf bytes.Buffer
+	want := Frame{Opcode: OpBinary, Payload: []byte{0x00, 0xFF, 0x10}}
+	if err := WriteUnmasked(&buf, want); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Opcode != want.Opcode || !bytes.Equal(got.Payload, want.Payload) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteMasked_ProducesWireBytesWithTheMaskBitSet(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMasked(&buf, Frame{Opcode: OpText, Payload: []byte("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	wire := buf.Bytes()
+	if wire[1]&0x80 == 0 {
+		t.Error("expected the mask bit to be set on a client-to-server frame")
+	}
+}
+
+func TestWriteUnmasked_ProducesWireBytesWithoutTheMaskBit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteUnmasked(&buf, Frame{Opcode: OpText, Payload: []byte("hi")}); err != nil {
+		t.Fatal(err)
+	}
+	wire := buf.Bytes()
+	if wire[1]&0x80 != 0 {
+		t.Error("expected the mask bit to be unset on a server-to-client frame")
+	}
+}
+
+func TestRoundTrip_HandlesA16BitExtendedLengthPayload(t *testing.T) {
+	var buf bytes.Buffer
+	payload := bytes.Repeat([]byte("x"), 1000)
+	if err := WriteMasked(&buf, Frame{Opcode: OpBinary, Payload: payload}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got.Payload, payload) {
+		t.Error("extended-length payload didn't round-trip intact")
+	}
+}
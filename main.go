@@ -0,0 +1,13 @@
+// Command http sends HTTP requests described by .http files from the
+// command line.
+package main
+
+import (
+	"os"
+
+	"github.com/adamijak/http/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}